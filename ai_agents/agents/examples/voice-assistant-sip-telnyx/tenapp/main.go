@@ -21,9 +21,51 @@ func main() {
 	logConfig.Format = "plain"
 	logConfig.Color = true
 
-	// Add log handler
+	// Add log handlers. The console handler is kept for local development;
+	// the JSON and file handlers give us structured, durable logs in
+	// deployed environments, and the syslog handler ships warnings and
+	// errors to the host's log aggregator.
 	tenApp.AddLogHandler(log.NewConsoleHandler(logConfig))
 
+	jsonConfig := log.NewConfig()
+	jsonConfig.Level = log.InfoLevel
+	tenApp.AddLogHandler(log.NewJSONHandler(jsonConfig, os.Stdout))
+
+	fileConfig := log.NewConfig()
+	fileConfig.Level = log.DebugLevel
+	tenApp.AddLogHandler(log.NewFileHandler(fileConfig, log.FileHandlerOptions{
+		Path:       "logs/agent_demo.log",
+		MaxSizeMB:  100,
+		MaxAgeDays: 7,
+		Compress:   true,
+	}))
+
+	if handler, err := log.NewSyslogHandler(log.NewConfig(), log.SyslogOptions{
+		Network:  "udp",
+		Addr:     "localhost:514",
+		Tag:      "agent_demo",
+		MinLevel: log.WarnLevel,
+	}); err != nil {
+		log.Error("Failed to initialize syslog handler:", err)
+	} else {
+		tenApp.AddLogHandler(handler)
+	}
+
+	// Expose the baseline extension metrics (ten_ext_cmd_total,
+	// ten_ext_cmd_duration_seconds, ten_ext_property_ops_total,
+	// ten_ext_log_total) plus any custom counters the extensions register
+	// via tenEnv.Counter, in OpenMetrics text format. Extensions have no
+	// histogram or gauge API.
+	if err := tenApp.EnableMetrics(":9464", "/metrics"); err != nil {
+		log.Error("Failed to enable metrics:", err)
+	}
+
+	// Bridge ten_framework/ten_runtime extensions' TenEnv.Log calls and
+	// tenEnv.Counter metrics into this App's own handlers/registry, so a
+	// graph's extensions show up on the same log handlers and /metrics
+	// endpoint configured above.
+	tenApp.BridgeTenRuntime()
+
 	// Load and run the app
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -50,4 +92,4 @@ func main() {
 	// Give some time for graceful shutdown
 	time.Sleep(time.Second)
 	log.Info("Application shutdown complete")
-}
\ No newline at end of file
+}
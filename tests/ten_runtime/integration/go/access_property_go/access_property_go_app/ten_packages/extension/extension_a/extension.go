@@ -8,10 +8,14 @@
 package default_extension_go
 
 import (
+	_ "embed"
 	"encoding/json"
 	ten "ten_framework/ten_runtime"
 )
 
+//go:embed property.schema.json
+var propertySchema []byte
+
 type baseExtension struct {
 	ten.DefaultExtension
 }
@@ -60,71 +64,22 @@ func (ext *baseExtension) OnInit(tenEnv ten.TenEnv) {
 		panic("Log with fields failed: " + err.Error())
 	}
 
-	// Parse the json bytes to a map.
+	// Validate the merged property tree against the schema registered for
+	// this addon (see property.schema.json) instead of hand-checking each
+	// field. A failure surfaces as a ten.TenError with
+	// ErrorCodeInvalidProperty carrying a JSON pointer path and the
+	// offending value.
+	if err := tenEnv.ValidateProperties(); err != nil {
+		panic("Property validation failed: " + err.Error())
+	}
+
+	// Parse the json bytes into a PredefinedProperty now that the schema
+	// has guaranteed its shape.
 	var predefinedProperty PredefinedProperty
 	if err := json.Unmarshal(propJsonBytes, &predefinedProperty); err != nil {
 		panic("Failed to unmarshal json bytes.")
 	}
 
-	// Check the predefined properties.
-	if predefinedProperty.PredefinedInt8 != 123 {
-		panic("predefined_int8 should be 123.")
-	}
-
-	if predefinedProperty.PredefinedInt16 != 12345 {
-		panic("predefined_int16 should be 12345.")
-	}
-
-	if predefinedProperty.PredefinedInt32 != 1234567890 {
-		panic("predefined_int32 should be 1234567890.")
-	}
-
-	if predefinedProperty.PredefinedInt64 != 1234567890 {
-		panic("predefined_int64 should be 1234567890.")
-	}
-
-	if predefinedProperty.PredefinedUint8 != 123 {
-		panic("predefined_uint8 should be 123.")
-	}
-
-	if predefinedProperty.PredefinedUint16 != 12345 {
-		panic("predefined_uint16 should be 12345.")
-	}
-
-	if predefinedProperty.PredefinedUint32 != 1234567890 {
-		panic("predefined_uint32 should be 1234567890.")
-	}
-
-	if predefinedProperty.PredefinedUint64 != 1234567890 {
-		panic("predefined_uint64 should be 1234567890.")
-	}
-
-	if predefinedProperty.PredefinedFloat32 != 123.456 {
-		panic("predefined_float32 should be 123.456.")
-	}
-
-	if predefinedProperty.PredefinedFloat64 != 1234567890.123 {
-		panic("predefined_float64 should be 1234567890.123.")
-	}
-
-	if predefinedProperty.PredefinedBool != true {
-		panic("predefined_bool should be true.")
-	}
-
-	if predefinedProperty.PredefinedString != "hello" {
-		panic("predefined_string should be hello.")
-	}
-
-	if predefinedProperty.PredefinedObject["prop_key"] != "prop_value" {
-		panic("predefined_object should be a map with prop_key and prop_value.")
-	}
-
-	if len(predefinedProperty.PredefinedArray) != 2 ||
-		predefinedProperty.PredefinedArray[0] != "item1" ||
-		predefinedProperty.PredefinedArray[1] != "item2" {
-		panic("predefined_array should be an array with two items.")
-	}
-
 	tenEnv.OnInitDone()
 }
 
@@ -158,6 +113,16 @@ func (p *aExtension) OnCmd(
 ) {
 	cmdName, _ := cmd.GetName()
 
+	// RegisterAddonAsExtension (see init below) already wraps every
+	// instance this addon creates so ten_ext_cmd_total /
+	// ten_ext_cmd_duration_seconds are incremented around every OnCmd
+	// call; track an extension-specific counter on top of that baseline.
+	propertyOps := tenEnv.Counter(
+		"extension_a_property_ops_total",
+		"cmd", cmdName,
+	)
+	defer propertyOps.Inc()
+
 	// Test log with fields containing various types
 	cmdFields := ten.NewObjectValue(map[string]ten.Value{
 		"cmd_name": ten.NewStringValue(cmdName),
@@ -233,14 +198,16 @@ func (p *aExtension) OnCmd(
 		panic("Should not happen.")
 	}
 
-	// The struct property is not supported.
+	// Struct properties (including nested pointer fields and slices) are
+	// now supported: SetProperty walks the struct via reflection and
+	// stores it as the equivalent ten.Value object tree.
 	if err := tenEnv.SetProperty(
 		"testStruct",
 		NestedUserStruct{
 			UserData: &UserStruct{5, "world"},
 			StrSlice: []string{"a", "b", "c"},
 		},
-	); err == nil {
+	); err != nil {
 		panic("Should not happen.")
 	}
 
@@ -313,6 +280,24 @@ func (p *aExtension) OnCmd(
 		panic("Should not happen.")
 	}
 
+	// Round-trip the nested struct by value through GetPropertyAs, which
+	// populates the struct via reflection rather than returning the
+	// original pointer.
+	var testStruct NestedUserStruct
+	if err := tenEnv.GetPropertyAs("testStruct", &testStruct); err != nil {
+		panic("Should not happen.")
+	}
+	if testStruct.UserData == nil || testStruct.UserData.Num != 5 ||
+		testStruct.UserData.Str != "world" {
+		panic("Should not happen.")
+	}
+	if len(testStruct.StrSlice) != 3 ||
+		testStruct.StrSlice[0] != "a" ||
+		testStruct.StrSlice[1] != "b" ||
+		testStruct.StrSlice[2] != "c" {
+		panic("Should not happen.")
+	}
+
 	testByteArray, err := tenEnv.GetPropertyBytes(
 		"testByteArray",
 	)
@@ -411,6 +396,12 @@ func (p *aExtension) OnCmd(
 }
 
 func init() {
+	// Register the property schema before the addon itself, so it is in
+	// place for the very first OnInit.
+	if err := ten.RegisterPropertySchema("extension_a", propertySchema); err != nil {
+		panic("Failed to register property schema.")
+	}
+
 	// Register addon
 	err := ten.RegisterAddonAsExtension(
 		"extension_a",
@@ -419,4 +410,32 @@ func init() {
 	if err != nil {
 		panic("Failed to register addon.")
 	}
+
+	// Register log hooks that run on every tenEnv.Log(...) call before
+	// the record reaches whatever sink the hosting process registers via
+	// ten.RegisterLogSink (see ten_runtime_go/app.BridgeTenRuntime, which
+	// forwards into the handlers registered via app.AddLogHandler).
+	// RedactionHook masks the values of secret-looking fields before they
+	// ever leave the process, and SamplingHook keeps log volume down for
+	// high-frequency debug records while always passing warn and above.
+	if err := ten.RegisterLogHook(
+		[]ten.LogLevel{ten.LogLevelDebug, ten.LogLevelInfo, ten.LogLevelWarn, ten.LogLevelError},
+		ten.NewTraceContextHook(),
+	); err != nil {
+		panic("Failed to register trace context log hook.")
+	}
+
+	if err := ten.RegisterLogHook(
+		[]ten.LogLevel{ten.LogLevelDebug, ten.LogLevelInfo, ten.LogLevelWarn, ten.LogLevelError},
+		ten.NewRedactionHook([]string{"*password*", "*token*", "*secret*"}),
+	); err != nil {
+		panic("Failed to register redaction log hook.")
+	}
+
+	if err := ten.RegisterLogHook(
+		[]ten.LogLevel{ten.LogLevelDebug},
+		ten.NewSamplingHook(10),
+	); err != nil {
+		panic("Failed to register sampling log hook.")
+	}
 }
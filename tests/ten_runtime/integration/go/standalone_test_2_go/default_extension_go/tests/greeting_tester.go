@@ -8,17 +8,38 @@
 package tests
 
 import (
+	_ "embed"
 	"fmt"
 	ten "ten_framework/ten_runtime"
 	"time"
+
+	"github.com/TEN-framework/ten_runtime_go/pkg/metrics"
 )
 
+//go:embed greeting_tester.schema.json
+var propertySchema []byte
+
+func init() {
+	// Register the property schema before any test runs, so the very
+	// first ValidateProperties call in OnStart has something to check
+	// the staged expected_greeting_msg/delay_ms properties against.
+	if err := ten.RegisterPropertySchema("GreetingTester", propertySchema); err != nil {
+		panic("Failed to register property schema.")
+	}
+}
+
 // GreetingTester is a tester for the Greeting extension.
 type GreetingTester struct {
 	ten.DefaultExtensionTester
 
 	ExpectedGreetingMsg string
 	DelayMs             uint32
+
+	// PushGatewayURL, if set, is where the test's own
+	// greeting_tester_cmd_total counter is pushed when the test stops,
+	// for short-lived runs (like this one) that exit before an HTTP
+	// scraper would ever reach them.
+	PushGatewayURL string
 }
 
 // OnStart is called when the test starts.
@@ -37,6 +58,16 @@ func (tester *GreetingTester) OnStart(tenEnvTester ten.TenEnvTester) {
 		panic("Log with fields failed: " + err.Error())
 	}
 
+	if err := tenEnvTester.SetProperty("expected_greeting_msg", tester.ExpectedGreetingMsg); err != nil {
+		panic("SetProperty failed: " + err.Error())
+	}
+	if err := tenEnvTester.SetProperty("delay_ms", tester.DelayMs); err != nil {
+		panic("SetProperty failed: " + err.Error())
+	}
+	if err := tenEnvTester.ValidateProperties(); err != nil {
+		panic("ValidateProperties failed: " + err.Error())
+	}
+
 	if tester.DelayMs > 0 {
 		time.Sleep(time.Duration(tester.DelayMs) * time.Millisecond)
 	}
@@ -59,6 +90,15 @@ func (tester *GreetingTester) OnStop(tenEnvTester ten.TenEnvTester) {
 		panic("Log with fields failed: " + err.Error())
 	}
 
+	if tester.PushGatewayURL != "" {
+		reg := metrics.NewRegistry(0)
+		reg.ImportExternal(ten.MetricsSnapshot())
+		client := metrics.NewPushGatewayClient(tester.PushGatewayURL, "greeting_tester", "greeting_tester_1")
+		if err := client.Push(reg); err != nil {
+			tenEnvTester.LogInfo("Failed to push metrics to pushgateway: " + err.Error())
+		}
+	}
+
 	tenEnvTester.OnStopDone()
 }
 
@@ -69,6 +109,7 @@ func (tester *GreetingTester) OnCmd(
 ) {
 	cmdName, _ := cmd.GetName()
 	tenEnv.LogInfo(fmt.Sprintf("OnCmd: %s", cmdName))
+	tenEnv.Counter("greeting_tester_cmd_total", "cmd", cmdName).Inc()
 
 	// Test log with fields containing command information
 	cmdFields := ten.NewObjectValue(map[string]ten.Value{
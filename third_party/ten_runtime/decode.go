@@ -0,0 +1,126 @@
+package ten
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// decodeStructValue populates target (addressable, reached through a
+// caller-supplied pointer) from a stored ten.Value tree, applying the
+// same loose numeric coercion as the fixed-width GetPropertyIntNN /
+// GetPropertyUintNN / GetPropertyFloatNN getters.
+func decodeStructValue(val Value, target reflect.Value) error {
+	if val == nil {
+		return fmt.Errorf("ten: cannot decode a nil property value")
+	}
+
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return decodeStructValue(val, target.Elem())
+	}
+
+	switch target.Kind() {
+	case reflect.Struct:
+		obj, ok := val.Interface().(map[string]Value)
+		if !ok {
+			return fmt.Errorf("ten: expected object to decode into struct, got %T", val.Interface())
+		}
+		rt := target.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			sf := rt.Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+			name, _ := parseJSONTag(sf)
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			fv, ok := obj[name]
+			if !ok {
+				continue
+			}
+			if err := decodeStructValue(fv, target.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		obj, ok := val.Interface().(map[string]Value)
+		if !ok {
+			return fmt.Errorf("ten: expected object to decode into map, got %T", val.Interface())
+		}
+		m := reflect.MakeMapWithSize(target.Type(), len(obj))
+		for k, item := range obj {
+			ev := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeStructValue(item, ev); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(k), ev)
+		}
+		target.Set(m)
+		return nil
+	case reflect.Slice:
+		if target.Type().Elem().Kind() == reflect.Uint8 {
+			buf, ok := val.Interface().([]byte)
+			if !ok {
+				return fmt.Errorf("ten: expected buf to decode into []byte, got %T", val.Interface())
+			}
+			target.SetBytes(append([]byte(nil), buf...))
+			return nil
+		}
+		items, ok := val.Interface().([]Value)
+		if !ok {
+			return fmt.Errorf("ten: expected array to decode into slice, got %T", val.Interface())
+		}
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeStructValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.String:
+		s, ok := val.Interface().(string)
+		if !ok {
+			return fmt.Errorf("ten: expected string, got %T", val.Interface())
+		}
+		target.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := val.Interface().(bool)
+		if !ok {
+			return fmt.Errorf("ten: expected bool, got %T", val.Interface())
+		}
+		target.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := coerceToInt64(val.Interface())
+		if err != nil {
+			return err
+		}
+		target.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := coerceToUint64(val.Interface())
+		if err != nil {
+			return err
+		}
+		target.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, err := coerceToFloat64(val.Interface())
+		if err != nil {
+			return err
+		}
+		target.SetFloat(n)
+		return nil
+	default:
+		return fmt.Errorf("ten: unsupported decode kind %s", target.Kind())
+	}
+}
@@ -0,0 +1,7 @@
+// Package ten is a local, pure-Go implementation of the subset of the
+// TEN runtime's Go binding (normally vendored from the ten-framework
+// monorepo via a replace directive) that this repo's extensions and
+// testers actually call: property storage/reflection, log hooks, schema
+// validation, and the TenEnv/Extension/Tester surface. It does not
+// implement the cgo bridge into the Rust runtime; that lives upstream.
+package ten
@@ -0,0 +1,23 @@
+package ten
+
+// ErrorCode identifies the class of failure carried by a TenError.
+type ErrorCode int
+
+const (
+	ErrorCodeOk ErrorCode = iota
+	ErrorCodeGeneric
+	ErrorCodeInvalidProperty
+)
+
+// TenError is the error type returned across the TenEnv API.
+type TenError struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (e *TenError) Error() string { return e.Message }
+
+// NewTenError builds a TenError with the given code and message.
+func NewTenError(code ErrorCode, message string) *TenError {
+	return &TenError{Code: code, Message: message}
+}
@@ -0,0 +1,109 @@
+package ten
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Extension is the lifecycle interface every TEN extension implements.
+type Extension interface {
+	OnInit(tenEnv TenEnv)
+	OnStart(tenEnv TenEnv)
+	OnStop(tenEnv TenEnv)
+	OnDeinit(tenEnv TenEnv)
+	OnCmd(tenEnv TenEnv, cmd Cmd)
+}
+
+// DefaultExtension provides no-op implementations of every Extension
+// method, so concrete extensions only need to override the ones they
+// care about.
+type DefaultExtension struct{}
+
+func (DefaultExtension) OnInit(tenEnv TenEnv)         { tenEnv.OnInitDone() }
+func (DefaultExtension) OnStart(tenEnv TenEnv)        {}
+func (DefaultExtension) OnStop(tenEnv TenEnv)         { tenEnv.OnStopDone() }
+func (DefaultExtension) OnDeinit(tenEnv TenEnv)       {}
+func (DefaultExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {}
+
+// ExtensionFactory creates a new Extension instance for the given
+// instance name.
+type ExtensionFactory func(name string) Extension
+
+// Addon is a registered extension factory, keyed by addon name.
+type Addon interface {
+	Create(name string) Extension
+}
+
+type defaultExtensionAddon struct {
+	factory ExtensionFactory
+}
+
+// NewDefaultExtensionAddon wraps factory as an Addon.
+func NewDefaultExtensionAddon(factory ExtensionFactory) Addon {
+	return &defaultExtensionAddon{factory: factory}
+}
+
+func (a *defaultExtensionAddon) Create(name string) Extension {
+	return a.factory(name)
+}
+
+var (
+	addonMu       sync.Mutex
+	addonRegistry = map[string]Addon{}
+)
+
+// RegisterAddonAsExtension registers addon under name, for later
+// instantiation by the runtime when a graph references it. Every
+// Extension it creates is wrapped so OnCmd is automatically counted and
+// timed under the baseline ten_ext_cmd_total / ten_ext_cmd_duration_seconds
+// metrics, regardless of what the concrete extension's own OnCmd does.
+func RegisterAddonAsExtension(name string, addon Addon) error {
+	addonMu.Lock()
+	defer addonMu.Unlock()
+	if _, exists := addonRegistry[name]; exists {
+		return NewTenError(ErrorCodeGeneric, fmt.Sprintf("addon %q already registered", name))
+	}
+	addonRegistry[name] = &instrumentedAddon{addonName: name, inner: addon}
+	return nil
+}
+
+// GetRegisteredAddon returns the addon registered under name via
+// RegisterAddonAsExtension, for tests and harnesses that need to
+// instantiate extensions directly rather than through a full graph.
+func GetRegisteredAddon(name string) (Addon, bool) {
+	addonMu.Lock()
+	defer addonMu.Unlock()
+	a, ok := addonRegistry[name]
+	return a, ok
+}
+
+type instrumentedAddon struct {
+	addonName string
+	inner     Addon
+}
+
+func (a *instrumentedAddon) Create(name string) Extension {
+	return &instrumentedExtension{Extension: a.inner.Create(name), addonName: a.addonName}
+}
+
+// instrumentedExtension wraps an Extension so every OnCmd call is counted
+// and timed under the baseline ten_ext_cmd_total /
+// ten_ext_cmd_duration_seconds metrics before delegating to the wrapped
+// extension's own OnCmd. ten_ext_cmd_duration_seconds is a counter that
+// accumulates the total seconds spent in OnCmd; it records no per-call
+// distribution or buckets.
+type instrumentedExtension struct {
+	Extension
+	addonName string
+}
+
+func (e *instrumentedExtension) OnCmd(tenEnv TenEnv, cmd Cmd) {
+	cmdName, _ := cmd.GetName()
+	start := time.Now()
+	defaultMetrics.counter("ten_ext_cmd_total", "addon", e.addonName, "cmd", cmdName).Inc()
+	defer func() {
+		defaultMetrics.counter("ten_ext_cmd_duration_seconds", "addon", e.addonName, "cmd", cmdName).Add(time.Since(start).Seconds())
+	}()
+	e.Extension.OnCmd(tenEnv, cmd)
+}
@@ -0,0 +1,40 @@
+package ten
+
+import "testing"
+
+type countingCmdExtension struct {
+	DefaultExtension
+	calls int
+}
+
+func (e *countingCmdExtension) OnCmd(tenEnv TenEnv, cmd Cmd) { e.calls++ }
+
+func TestRegisteredAddonInstrumentsBaselineCmdMetrics(t *testing.T) {
+	inner := &countingCmdExtension{}
+	if err := RegisterAddonAsExtension("test_addon", NewDefaultExtensionAddon(func(name string) Extension {
+		return inner
+	})); err != nil {
+		t.Fatalf("RegisterAddonAsExtension: %v", err)
+	}
+
+	addon, ok := GetRegisteredAddon("test_addon")
+	if !ok {
+		t.Fatalf("expected test_addon to be registered")
+	}
+
+	ext := addon.Create("test_addon_1")
+	te := newTenEnv("test_addon_1")
+	ext.OnCmd(te, NewCmd("greet"))
+
+	if inner.calls != 1 {
+		t.Fatalf("expected wrapped extension's OnCmd to run, got %d calls", inner.calls)
+	}
+
+	snap := MetricsSnapshot()
+	if snap["ten_ext_cmd_total,addon=test_addon,cmd=greet"] != 1 {
+		t.Fatalf("expected ten_ext_cmd_total to be incremented, snapshot: %+v", snap)
+	}
+	if _, ok := snap["ten_ext_cmd_duration_seconds,addon=test_addon,cmd=greet"]; !ok {
+		t.Fatalf("expected ten_ext_cmd_duration_seconds to be recorded, snapshot: %+v", snap)
+	}
+}
@@ -0,0 +1,289 @@
+package ten
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// LogLevel mirrors the runtime's log severities.
+type LogLevel int
+
+const (
+	LogLevelVerbose LogLevel = iota
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+	LogLevelFatal
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelVerbose:
+		return "verbose"
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	case LogLevelFatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// LogRecord is the mutable record passed through registered LogHooks
+// before it is handed to the app's log handlers.
+type LogRecord struct {
+	Level         LogLevel
+	Category      string
+	Message       string
+	Fields        Value
+	ExtensionName string
+	GraphID       string
+}
+
+// errDropRecord is a sentinel a LogHook can return from Fire to silently
+// drop the record (e.g. SamplingHook) without surfacing an error from
+// TenEnv.Log to the caller.
+var errDropRecord = errors.New("ten: log record dropped by hook")
+
+// LogHook mirrors the logrus Hook pattern: Fire runs, in registration
+// order, for every record whose level is in the hook's subscribed
+// levels. It may mutate the record in place, or abort dispatch by
+// returning an error, which TenEnv.Log then returns to its caller. The
+// drop sentinel is unexported, so only this package's own hooks (such as
+// SamplingHook) can drop a record silently.
+type LogHook interface {
+	Fire(record *LogRecord) error
+}
+
+type registeredHook struct {
+	levels map[LogLevel]bool
+	hook   LogHook
+}
+
+var (
+	hooksMu sync.Mutex
+	hooks   []registeredHook
+)
+
+// RegisterLogHook adds hook to the dispatch chain for the given levels.
+// Hooks run, in registration order, on every TenEnv.Log call before the
+// record reaches any handler.
+func RegisterLogHook(levels []LogLevel, hook LogHook) error {
+	if hook == nil {
+		return NewTenError(ErrorCodeGeneric, "hook must not be nil")
+	}
+	levelSet := make(map[LogLevel]bool, len(levels))
+	for _, l := range levels {
+		levelSet[l] = true
+	}
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, registeredHook{levels: levelSet, hook: hook})
+	return nil
+}
+
+func runHooks(record *LogRecord) (drop bool, err error) {
+	hooksMu.Lock()
+	chain := make([]registeredHook, len(hooks))
+	copy(chain, hooks)
+	hooksMu.Unlock()
+
+	for _, rh := range chain {
+		if !rh.levels[record.Level] {
+			continue
+		}
+		if err := rh.hook.Fire(record); err != nil {
+			if errors.Is(err, errDropRecord) {
+				return true, nil
+			}
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// LogSink receives every LogRecord that survives the hook chain. An app
+// embedding this package registers one via RegisterLogSink to forward
+// records into its own handler ecosystem (e.g.
+// github.com/TEN-framework/ten_runtime_go/app.BridgeTenRuntime); with no
+// sink registered, dispatchLog falls back to a bare stderr line, which is
+// all a standalone extension test (no app around it) gets.
+type LogSink func(record *LogRecord)
+
+var (
+	sinkMu sync.Mutex
+	sink   LogSink
+)
+
+// RegisterLogSink installs sink as the destination for every record that
+// survives the hook chain, replacing whatever sink (if any) was
+// registered before. There is exactly one sink per process, since there
+// is exactly one downstream handler chain to forward into.
+func RegisterLogSink(s LogSink) {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	sink = s
+}
+
+func currentSink() LogSink {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	return sink
+}
+
+func dispatchLog(record *LogRecord) error {
+	drop, err := runHooks(record)
+	if err != nil {
+		return err
+	}
+	if drop {
+		return nil
+	}
+	incLogOp(record.Level, record.Category)
+
+	if s := currentSink(); s != nil {
+		s(record)
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", record.Level, record.Message)
+	if record.Category != "" {
+		fmt.Fprintf(&b, " category=%s", record.Category)
+	}
+	if record.ExtensionName != "" {
+		fmt.Fprintf(&b, " extension=%s", record.ExtensionName)
+	}
+	println(b.String())
+	return nil
+}
+
+func fieldsOf(record *LogRecord) map[string]Value {
+	if record.Fields == nil {
+		return map[string]Value{}
+	}
+	obj, ok := record.Fields.Interface().(map[string]Value)
+	if !ok {
+		return map[string]Value{}
+	}
+	out := make(map[string]Value, len(obj))
+	for k, v := range obj {
+		out[k] = v
+	}
+	return out
+}
+
+// TraceContextHook injects OpenTelemetry-style trace/span ids carried on
+// an ambient source into every record's fields.
+type TraceContextHook struct {
+	// TraceID, when set, supplies the current trace/span id pair. It
+	// defaults to returning ok=false (no ambient trace context), since
+	// this package has no OpenTelemetry dependency of its own.
+	TraceID func() (traceID, spanID string, ok bool)
+}
+
+// NewTraceContextHook builds a TraceContextHook with no ambient source
+// configured; set TraceID to wire in a real tracer.
+func NewTraceContextHook() *TraceContextHook {
+	return &TraceContextHook{}
+}
+
+func (h *TraceContextHook) Fire(record *LogRecord) error {
+	if h.TraceID == nil {
+		return nil
+	}
+	traceID, spanID, ok := h.TraceID()
+	if !ok {
+		return nil
+	}
+	fields := fieldsOf(record)
+	fields["trace_id"] = NewStringValue(traceID)
+	fields["span_id"] = NewStringValue(spanID)
+	record.Fields = NewObjectValue(fields)
+	return nil
+}
+
+// RedactionHook blanks out field values whose key matches one of the
+// configured globs (e.g. "*password*", "*token*").
+type RedactionHook struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactionHook compiles keyGlobs (glob syntax: "*" matches any run
+// of characters, case-insensitive) into a RedactionHook.
+func NewRedactionHook(keyGlobs []string) *RedactionHook {
+	h := &RedactionHook{}
+	for _, glob := range keyGlobs {
+		h.patterns = append(h.patterns, globToRegexp(glob))
+	}
+	return h
+}
+
+func (h *RedactionHook) Fire(record *LogRecord) error {
+	if record.Fields == nil {
+		return nil
+	}
+	fields := fieldsOf(record)
+	for k := range fields {
+		if h.matches(k) {
+			fields[k] = NewStringValue("[REDACTED]")
+		}
+	}
+	record.Fields = NewObjectValue(fields)
+	return nil
+}
+
+func (h *RedactionHook) matches(key string) bool {
+	for _, p := range h.patterns {
+		if p.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func globToRegexp(glob string) *regexp.Regexp {
+	escaped := regexp.QuoteMeta(glob)
+	escaped = strings.ReplaceAll(escaped, `\*`, ".*")
+	return regexp.MustCompile("(?i)^" + escaped + "$")
+}
+
+// SamplingHook keeps 1 in n records below LogLevelWarn, always passing
+// warn and above through untouched.
+type SamplingHook struct {
+	n       int
+	mu      sync.Mutex
+	counter uint64
+}
+
+// NewSamplingHook builds a SamplingHook that keeps every nth record.
+func NewSamplingHook(n int) *SamplingHook {
+	if n < 1 {
+		n = 1
+	}
+	return &SamplingHook{n: n}
+}
+
+func (h *SamplingHook) Fire(record *LogRecord) error {
+	if record.Level >= LogLevelWarn {
+		return nil
+	}
+	h.mu.Lock()
+	h.counter++
+	keep := h.counter%uint64(h.n) == 0
+	h.mu.Unlock()
+	if !keep {
+		return errDropRecord
+	}
+	return nil
+}
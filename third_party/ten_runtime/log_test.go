@@ -0,0 +1,55 @@
+package ten
+
+import "testing"
+
+func TestRedactionHookMasksMatchingFields(t *testing.T) {
+	hook := NewRedactionHook([]string{"*password*", "*token*"})
+	record := &LogRecord{
+		Level: LogLevelInfo,
+		Fields: NewObjectValue(map[string]Value{
+			"user_password": NewStringValue("hunter2"),
+			"api_token":     NewStringValue("abc123"),
+			"username":      NewStringValue("alice"),
+		}),
+	}
+
+	if err := hook.Fire(record); err != nil {
+		t.Fatalf("Fire: %v", err)
+	}
+
+	fields := record.Fields.Interface().(map[string]Value)
+	if fields["user_password"].Interface().(string) != "[REDACTED]" {
+		t.Fatalf("expected user_password to be redacted")
+	}
+	if fields["api_token"].Interface().(string) != "[REDACTED]" {
+		t.Fatalf("expected api_token to be redacted")
+	}
+	if fields["username"].Interface().(string) != "alice" {
+		t.Fatalf("expected username to be left alone, got %v", fields["username"])
+	}
+}
+
+func TestSamplingHookKeepsEveryNth(t *testing.T) {
+	hook := NewSamplingHook(3)
+	kept := 0
+	for i := 0; i < 9; i++ {
+		record := &LogRecord{Level: LogLevelDebug}
+		err := hook.Fire(record)
+		if err == nil {
+			kept++
+		} else if err != errDropRecord {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if kept != 3 {
+		t.Fatalf("expected 3 kept records out of 9, got %d", kept)
+	}
+}
+
+func TestSamplingHookAlwaysKeepsWarnAndAbove(t *testing.T) {
+	hook := NewSamplingHook(100)
+	record := &LogRecord{Level: LogLevelWarn}
+	if err := hook.Fire(record); err != nil {
+		t.Fatalf("expected warn-level records to always pass, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package ten
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// Counter is a monotonically increasing metric, as returned by
+// TenEnv.Counter and used internally for the baseline ten_ext_* metrics
+// tapped around SetProperty/GetPropertyXxx and Log.
+type Counter interface {
+	Inc()
+	Add(delta float64)
+}
+
+type counterImpl struct {
+	mu    sync.Mutex
+	value float64
+}
+
+func (c *counterImpl) Inc()              { c.Add(1) }
+func (c *counterImpl) Add(delta float64) { c.mu.Lock(); c.value += delta; c.mu.Unlock() }
+func (c *counterImpl) get() float64      { c.mu.Lock(); defer c.mu.Unlock(); return c.value }
+
+// maxMetricSeries bounds cardinality: once a registry holds this many
+// series in total (across all metric names), the label values of every
+// further new series are hashed down to one of overflowBuckets
+// fixed-width buckets instead of growing the series set without limit.
+const maxMetricSeries = 10000
+
+// overflowBuckets is the fixed number of buckets overflowing label values
+// collapse into, so cardinality actually stays bounded once maxMetricSeries
+// is reached (a per-value hash would merely rename the unbounded growth).
+const overflowBuckets = 8
+
+type metricsRegistry struct {
+	mu       sync.Mutex
+	counters map[string]*counterImpl
+}
+
+var defaultMetrics = &metricsRegistry{counters: map[string]*counterImpl{}}
+
+func metricKey(name string, labelPairs []string) string {
+	key := name
+	for i := 0; i+1 < len(labelPairs); i += 2 {
+		key += fmt.Sprintf(",%s=%s", labelPairs[i], labelPairs[i+1])
+	}
+	return key
+}
+
+func hashLabelValue(v string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(v))
+	return fmt.Sprintf("overflow%d", h.Sum32()%overflowBuckets)
+}
+
+func (r *metricsRegistry) counter(name string, labelPairs ...string) Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := metricKey(name, labelPairs)
+	if c, ok := r.counters[key]; ok {
+		return c
+	}
+
+	if len(r.counters) >= maxMetricSeries {
+		bounded := make([]string, len(labelPairs))
+		copy(bounded, labelPairs)
+		for i := 1; i < len(bounded); i += 2 {
+			bounded[i] = hashLabelValue(bounded[i])
+		}
+		key = metricKey(name, bounded)
+		if c, ok := r.counters[key]; ok {
+			return c
+		}
+	}
+
+	c := &counterImpl{}
+	r.counters[key] = c
+	return c
+}
+
+// MetricsSnapshot returns the current value of every registered counter,
+// keyed by its metric name plus label pairs. It lets tests (and an
+// in-process collector) observe the baseline ten_ext_* counters without
+// scraping an HTTP endpoint.
+func MetricsSnapshot() map[string]float64 {
+	defaultMetrics.mu.Lock()
+	defer defaultMetrics.mu.Unlock()
+
+	out := make(map[string]float64, len(defaultMetrics.counters))
+	for k, c := range defaultMetrics.counters {
+		out[k] = c.get()
+	}
+	return out
+}
+
+func incPropertyOp(op, typeName string) {
+	defaultMetrics.counter("ten_ext_property_ops_total", "op", op, "type", typeName).Inc()
+}
+
+func incLogOp(level LogLevel, category string) {
+	defaultMetrics.counter("ten_ext_log_total", "level", level.String(), "category", category).Inc()
+}
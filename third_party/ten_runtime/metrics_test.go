@@ -0,0 +1,28 @@
+package ten
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCounterAccumulatesAndSnapshots(t *testing.T) {
+	te := newTenEnv("extension_test")
+	c := te.Counter("test_requests_total", "cmd", "greet")
+	c.Inc()
+	c.Add(2)
+
+	snap := MetricsSnapshot()
+	if snap["test_requests_total,cmd=greet"] != 3 {
+		t.Fatalf("unexpected snapshot: %+v", snap)
+	}
+}
+
+func TestMetricsRegistryBoundsCardinality(t *testing.T) {
+	reg := &metricsRegistry{counters: map[string]*counterImpl{}}
+	for i := 0; i < maxMetricSeries+100; i++ {
+		reg.counter("test_cardinality_total", "id", fmt.Sprintf("v%d", i))
+	}
+	if got := len(reg.counters); got > maxMetricSeries+overflowBuckets {
+		t.Fatalf("expected cardinality bounded to %d+%d, got %d", maxMetricSeries, overflowBuckets, got)
+	}
+}
@@ -0,0 +1,239 @@
+package ten
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// defaultMaxStructDepth bounds the recursion used by encodeStructValue
+// and decodeStructValue, guarding against runaway recursion on malformed
+// or pathologically nested inputs.
+const defaultMaxStructDepth = 32
+
+type propertyStore struct {
+	mu     sync.RWMutex
+	values map[string]Value
+	ptrs   map[string]interface{}
+}
+
+func newPropertyStore() *propertyStore {
+	return &propertyStore{values: map[string]Value{}, ptrs: map[string]interface{}{}}
+}
+
+func (s *propertyStore) set(path string, v Value) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[path] = v
+}
+
+func (s *propertyStore) setPtr(path string, v interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ptrs[path] = v
+}
+
+func (s *propertyStore) get(path string) (Value, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[path]
+	return v, ok
+}
+
+func (s *propertyStore) getPtr(path string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.ptrs[path]
+	return v, ok
+}
+
+func (s *propertyStore) snapshot() map[string]Value {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]Value, len(s.values))
+	for k, v := range s.values {
+		out[k] = v
+	}
+	return out
+}
+
+type jsonTagOptions struct{ omitempty bool }
+
+func parseJSONTag(sf reflect.StructField) (string, jsonTagOptions) {
+	tag := sf.Tag.Get("json")
+	if tag == "" {
+		return "", jsonTagOptions{}
+	}
+	parts := strings.Split(tag, ",")
+	opts := jsonTagOptions{}
+	for _, p := range parts[1:] {
+		if p == "omitempty" {
+			opts.omitempty = true
+		}
+	}
+	return parts[0], opts
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// encodeStructValue converts an arbitrary Go value (struct, pointer to
+// struct, map, slice, or primitive) into the equivalent ten.Value tree,
+// honoring `json:"..."` tags (including "-" and "omitempty"). It detects
+// self-referential cycles via pointer identity, scoped to the current
+// path rather than the whole call: a pointer is marked seen on entry and
+// unmarked once its subtree finishes encoding, so two sibling fields
+// pointing at the same (non-cyclic) value don't collide. It also bails
+// out past maxDepth rather than recursing forever on malformed input.
+func encodeStructValue(rv reflect.Value, depth int, maxDepth int, seen map[uintptr]bool) (Value, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("ten: property value exceeds max encode depth (%d)", maxDepth)
+	}
+
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return NewObjectValue(nil), nil
+		}
+		ptr := rv.Pointer()
+		if seen[ptr] {
+			return nil, fmt.Errorf("ten: cycle detected while encoding property at depth %d", depth)
+		}
+		seen[ptr] = true
+		defer delete(seen, ptr)
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		fields := map[string]Value{}
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			sf := rt.Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+			name, opts := parseJSONTag(sf)
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			fv := rv.Field(i)
+			if opts.omitempty && isEmptyValue(fv) {
+				continue
+			}
+			encoded, err := encodeStructValue(fv, depth+1, maxDepth, seen)
+			if err != nil {
+				return nil, err
+			}
+			fields[name] = encoded
+		}
+		return NewObjectValue(fields), nil
+	case reflect.Map:
+		obj := map[string]Value{}
+		for _, key := range rv.MapKeys() {
+			encoded, err := encodeStructValue(rv.MapIndex(key), depth+1, maxDepth, seen)
+			if err != nil {
+				return nil, err
+			}
+			obj[fmt.Sprintf("%v", key.Interface())] = encoded
+		}
+		return NewObjectValue(obj), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return NewArrayValue(nil), nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return NewBufValue(append([]byte(nil), rv.Bytes()...)), nil
+		}
+		items := make([]Value, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			encoded, err := encodeStructValue(rv.Index(i), depth+1, maxDepth, seen)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = encoded
+		}
+		return NewArrayValue(items), nil
+	case reflect.Array:
+		items := make([]Value, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			encoded, err := encodeStructValue(rv.Index(i), depth+1, maxDepth, seen)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = encoded
+		}
+		return NewArrayValue(items), nil
+	case reflect.String:
+		return NewStringValue(rv.String()), nil
+	case reflect.Bool:
+		return NewBoolValue(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewInt64Value(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return NewUint64Value(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return NewFloat64Value(rv.Float()), nil
+	case reflect.Interface:
+		if rv.IsNil() {
+			return NewObjectValue(nil), nil
+		}
+		return encodeStructValue(rv.Elem(), depth, maxDepth, seen)
+	default:
+		return nil, fmt.Errorf("ten: unsupported kind %s while encoding property", rv.Kind())
+	}
+}
+
+func coerceToInt64(v interface{}) (int64, error) {
+	switch x := v.(type) {
+	case int64:
+		return x, nil
+	case uint64:
+		return int64(x), nil
+	case float64:
+		return int64(x), nil
+	}
+	return 0, fmt.Errorf("ten: cannot coerce %T to an integer", v)
+}
+
+func coerceToUint64(v interface{}) (uint64, error) {
+	switch x := v.(type) {
+	case uint64:
+		return x, nil
+	case int64:
+		return uint64(x), nil
+	case float64:
+		return uint64(x), nil
+	}
+	return 0, fmt.Errorf("ten: cannot coerce %T to an unsigned integer", v)
+}
+
+func coerceToFloat64(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case int64:
+		return float64(x), nil
+	case uint64:
+		return float64(x), nil
+	}
+	return 0, fmt.Errorf("ten: cannot coerce %T to a float", v)
+}
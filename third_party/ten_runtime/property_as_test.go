@@ -0,0 +1,31 @@
+package ten
+
+import "testing"
+
+type testNestedStruct struct {
+	UserData *nestedUser
+	StrSlice []string
+}
+
+func TestSetGetPropertyStructRoundTrip(t *testing.T) {
+	te := newTenEnv("extension_test")
+
+	if err := te.SetProperty("testStruct", testNestedStruct{
+		UserData: &nestedUser{Num: 5, Str: "world"},
+		StrSlice: []string{"a", "b", "c"},
+	}); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+
+	var out testNestedStruct
+	if err := te.GetPropertyAs("testStruct", &out); err != nil {
+		t.Fatalf("GetPropertyAs: %v", err)
+	}
+
+	if out.UserData == nil || out.UserData.Num != 5 || out.UserData.Str != "world" {
+		t.Fatalf("unexpected UserData: %+v", out.UserData)
+	}
+	if len(out.StrSlice) != 3 || out.StrSlice[0] != "a" || out.StrSlice[2] != "c" {
+		t.Fatalf("unexpected StrSlice: %v", out.StrSlice)
+	}
+}
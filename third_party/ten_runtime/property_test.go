@@ -0,0 +1,62 @@
+package ten
+
+import "testing"
+
+type nestedUser struct {
+	Num int
+	Str string
+}
+
+func TestSetPropertyPointerPreservesIdentity(t *testing.T) {
+	te := newTenEnv("extension_test")
+	original := &nestedUser{Num: 2, Str: "hello"}
+
+	if err := te.SetProperty("testObject", original); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+
+	got, err := te.GetPropertyPtr("testObject")
+	if err != nil {
+		t.Fatalf("GetPropertyPtr: %v", err)
+	}
+	if got.(*nestedUser) != original {
+		t.Fatalf("expected the original pointer back")
+	}
+}
+
+func TestEncodeStructValueDetectsCycles(t *testing.T) {
+	type cyclic struct {
+		Self *cyclic
+	}
+	c := &cyclic{}
+	c.Self = c
+
+	te := newTenEnv("extension_test")
+	if err := te.SetProperty("cyclic", c); err == nil {
+		t.Fatalf("expected cycle detection error, got nil")
+	}
+}
+
+func TestSetPropertySharedPointerIsNotACycle(t *testing.T) {
+	type outer struct {
+		A *nestedUser
+		B *nestedUser
+	}
+	shared := &nestedUser{Num: 7, Str: "shared"}
+
+	te := newTenEnv("extension_test")
+	if err := te.SetProperty("diamond", &outer{A: shared, B: shared}); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+}
+
+func TestSetPropertyByteSliceIsBuf(t *testing.T) {
+	te := newTenEnv("extension_test")
+	if err := te.SetProperty("testByteArray", []byte("hello")); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+	got, err := te.GetPropertyBytes("testByteArray")
+	if err != nil || string(got) != "hello" {
+		t.Fatalf("GetPropertyBytes = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,240 @@
+package ten
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// propertySchema is a parsed JSON Schema, restricted to the subset this
+// validator understands: type, required, minimum/maximum, enum, const,
+// pattern, minItems/maxItems, items, properties, and $ref into
+// "definitions" (so several extensions in a graph can share shapes like
+// PredefinedObject).
+type propertySchema struct {
+	raw         map[string]interface{}
+	definitions map[string]interface{}
+}
+
+var (
+	schemaMu       sync.Mutex
+	schemaRegistry = map[string]*propertySchema{}
+)
+
+// RegisterPropertySchema parses and stores the JSON Schema document under
+// addonName. TenEnv.ValidateProperties looks the schema up by the
+// TenEnv's extension name and TenEnvTester.ValidateProperties by the
+// tester's name, so it applies to an extension or tester whose name
+// equals addonName.
+func RegisterPropertySchema(addonName string, schema []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(schema, &raw); err != nil {
+		return NewTenError(ErrorCodeInvalidProperty, "invalid schema JSON: "+err.Error())
+	}
+	defs, _ := raw["definitions"].(map[string]interface{})
+
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	schemaRegistry[addonName] = &propertySchema{raw: raw, definitions: defs}
+	return nil
+}
+
+func getPropertySchema(addonName string) (*propertySchema, bool) {
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	s, ok := schemaRegistry[addonName]
+	return s, ok
+}
+
+// Validate checks values (the merged property tree) against the schema,
+// returning the first failure as a *TenError with ErrorCodeInvalidProperty,
+// a JSON pointer path, and the offending value.
+func (s *propertySchema) Validate(values map[string]Value) error {
+	converted, err := valueToJSONInterface(NewObjectValue(values))
+	if err != nil {
+		return NewTenError(ErrorCodeInvalidProperty, err.Error())
+	}
+	return validateAgainstSchema("", converted, s.raw, s.definitions)
+}
+
+func resolveSchema(schema map[string]interface{}, defs map[string]interface{}) (map[string]interface{}, error) {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema, nil
+	}
+	const prefix = "#/definitions/"
+	if len(ref) <= len(prefix) || ref[:len(prefix)] != prefix {
+		return nil, fmt.Errorf("ten: unsupported $ref %q", ref)
+	}
+	name := ref[len(prefix):]
+	def, ok := defs[name].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ten: unresolved $ref %q", ref)
+	}
+	return def, nil
+}
+
+func validateAgainstSchema(pointer string, value interface{}, schema map[string]interface{}, defs map[string]interface{}) error {
+	schema, err := resolveSchema(schema, defs)
+	if err != nil {
+		return NewTenError(ErrorCodeInvalidProperty, err.Error())
+	}
+
+	if expected, ok := schema["type"].(string); ok {
+		if !jsonTypeMatches(expected, value) {
+			return invalidPropertyError(pointer, value, fmt.Sprintf("expected type %q", expected))
+		}
+	}
+
+	if constVal, ok := schema["const"]; ok {
+		if !jsonEqual(constVal, value) {
+			return invalidPropertyError(pointer, value, fmt.Sprintf("expected const %v", constVal))
+		}
+	}
+
+	if enumVal, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, e := range enumVal {
+			if jsonEqual(e, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return invalidPropertyError(pointer, value, "value not in enum")
+		}
+	}
+
+	if n, ok := toFloat(value); ok {
+		if min, ok := schema["minimum"]; ok {
+			if minF, ok := toFloat(min); ok && n < minF {
+				return invalidPropertyError(pointer, value, fmt.Sprintf("below minimum %v", min))
+			}
+		}
+		if max, ok := schema["maximum"]; ok {
+			if maxF, ok := toFloat(max); ok && n > maxF {
+				return invalidPropertyError(pointer, value, fmt.Sprintf("above maximum %v", max))
+			}
+		}
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				key, _ := r.(string)
+				if _, present := typed[key]; !present {
+					return invalidPropertyError(pointer+"/"+key, nil, "required property missing")
+				}
+			}
+		}
+		keys := make([]string, 0, len(typed))
+		for k := range typed {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			propSchema, ok := props[k].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := validateAgainstSchema(pointer+"/"+k, typed[k], propSchema, defs); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if minItems, ok := schema["minItems"]; ok {
+			if minF, ok := toFloat(minItems); ok && float64(len(typed)) < minF {
+				return invalidPropertyError(pointer, value, fmt.Sprintf("fewer than minItems %v", minItems))
+			}
+		}
+		if maxItems, ok := schema["maxItems"]; ok {
+			if maxF, ok := toFloat(maxItems); ok && float64(len(typed)) > maxF {
+				return invalidPropertyError(pointer, value, fmt.Sprintf("more than maxItems %v", maxItems))
+			}
+		}
+		if itemSchema, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range typed {
+				if err := validateAgainstSchema(fmt.Sprintf("%s/%d", pointer, i), item, itemSchema, defs); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return NewTenError(ErrorCodeInvalidProperty, "invalid pattern: "+err.Error())
+			}
+			if !re.MatchString(typed) {
+				return invalidPropertyError(pointer, value, fmt.Sprintf("does not match pattern %q", pattern))
+			}
+		}
+	}
+
+	return nil
+}
+
+func invalidPropertyError(pointer string, value interface{}, reason string) error {
+	if pointer == "" {
+		pointer = "/"
+	}
+	return &TenError{
+		Code:    ErrorCodeInvalidProperty,
+		Message: fmt.Sprintf("invalid property at %s: %s (value=%v)", pointer, reason, value),
+	}
+}
+
+func jsonTypeMatches(expected string, value interface{}) bool {
+	switch expected {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		n, ok := toFloat(value)
+		return ok && n == float64(int64(n))
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	default:
+		return true
+	}
+}
+
+func jsonEqual(a, b interface{}) bool {
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok && bok {
+		return af == bf
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,49 @@
+package ten
+
+import "testing"
+
+func TestValidatePropertiesAgainstSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"predefined_int8": {"type": "integer", "const": 123},
+			"predefined_object": {"$ref": "#/definitions/predefined_object"}
+		},
+		"required": ["predefined_int8", "predefined_object"],
+		"definitions": {
+			"predefined_object": {
+				"type": "object",
+				"properties": {"prop_key": {"type": "string", "const": "prop_value"}},
+				"required": ["prop_key"]
+			}
+		}
+	}`)
+
+	if err := RegisterPropertySchema("schema_test_extension", schema); err != nil {
+		t.Fatalf("RegisterPropertySchema: %v", err)
+	}
+
+	te := newTenEnv("schema_test_extension")
+	if err := te.SetProperty("predefined_int8", int8(123)); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+	if err := te.SetProperty("predefined_object", map[string]string{"prop_key": "prop_value"}); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+
+	if err := te.ValidateProperties(); err != nil {
+		t.Fatalf("ValidateProperties: %v", err)
+	}
+
+	if err := te.SetProperty("predefined_int8", int8(1)); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+	err := te.ValidateProperties()
+	if err == nil {
+		t.Fatalf("expected ValidateProperties to fail for a mismatched const")
+	}
+	tenErr, ok := err.(*TenError)
+	if !ok || tenErr.Code != ErrorCodeInvalidProperty {
+		t.Fatalf("expected ErrorCodeInvalidProperty, got %v", err)
+	}
+}
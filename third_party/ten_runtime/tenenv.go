@@ -0,0 +1,417 @@
+package ten
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// StatusCode is the result status of a Cmd.
+type StatusCode int
+
+const (
+	StatusCodeOk StatusCode = iota
+	StatusCodeError
+)
+
+// Cmd is an inbound command.
+type Cmd interface {
+	GetName() (string, error)
+	GetPropertyString(path string) (string, error)
+}
+
+type cmd struct {
+	name  string
+	props map[string]string
+}
+
+// NewCmd builds a Cmd with the given name, for use by testers that need
+// to synthesize inbound commands.
+func NewCmd(name string) Cmd {
+	return &cmd{name: name, props: map[string]string{}}
+}
+
+func (c *cmd) GetName() (string, error) { return c.name, nil }
+
+func (c *cmd) GetPropertyString(path string) (string, error) {
+	return c.props[path], nil
+}
+
+// CmdResult carries the outcome of handling a Cmd back to the caller.
+type CmdResult interface {
+	SetPropertyString(path string, value string) error
+}
+
+type cmdResult struct {
+	statusCode StatusCode
+	props      map[string]string
+}
+
+// NewCmdResult builds a CmdResult for the given cmd with the given
+// status code.
+func NewCmdResult(statusCode StatusCode, cmd Cmd) (CmdResult, error) {
+	return &cmdResult{statusCode: statusCode, props: map[string]string{}}, nil
+}
+
+func (r *cmdResult) SetPropertyString(path string, value string) error {
+	r.props[path] = value
+	return nil
+}
+
+// TenEnv is the handle an Extension uses to talk back to the runtime:
+// logging, property access, metrics, and lifecycle/result callbacks.
+type TenEnv interface {
+	Log(level LogLevel, msg string, category *string, fields *Value, extra *Value) error
+	LogDebug(msg string)
+	LogInfo(msg string)
+	LogError(msg string)
+
+	SetProperty(path string, v interface{}) error
+	GetPropertyBool(path string) (bool, error)
+	GetPropertyString(path string) (string, error)
+	GetPropertyBytes(path string) ([]byte, error)
+	GetPropertyPtr(path string) (interface{}, error)
+	GetPropertyAs(path string, out interface{}) error
+	GetPropertyInt8(path string) (int8, error)
+	GetPropertyInt16(path string) (int16, error)
+	GetPropertyInt32(path string) (int32, error)
+	GetPropertyInt64(path string) (int64, error)
+	GetPropertyUint32(path string) (uint32, error)
+	GetPropertyUint64(path string) (uint64, error)
+	GetPropertyFloat32(path string) (float32, error)
+	GetPropertyFloat64(path string) (float64, error)
+	GetPropertyToJSONBytes(path string) ([]byte, error)
+
+	// Counter returns a Counter scoped to name and the given label pairs
+	// (key, value, key, value, ...), backed by the process-wide metrics
+	// registry so repeated calls with the same name/labels share a series.
+	Counter(name string, labelPairs ...string) Counter
+
+	// ValidateProperties checks every property currently set against the
+	// JSON Schema registered for this extension via
+	// RegisterPropertySchema, if any. It is a no-op if no schema was
+	// registered.
+	ValidateProperties() error
+
+	OnInitDone()
+	OnStopDone()
+	ReturnResult(result CmdResult, cmd Cmd) error
+}
+
+type tenEnvImpl struct {
+	extensionName string
+	store         *propertyStore
+}
+
+// newTenEnv builds a TenEnv for the named extension. Extensions normally
+// receive their TenEnv from the runtime rather than constructing one
+// directly.
+func newTenEnv(extensionName string) *tenEnvImpl {
+	return &tenEnvImpl{extensionName: extensionName, store: newPropertyStore()}
+}
+
+// NewTenEnvForTest builds a TenEnv for the named extension, for tests and
+// bridge code outside this package (such as
+// ten_runtime_go/app.BridgeTenRuntime's tests) that need to drive
+// Log/SetProperty/Counter without a full runtime instantiating one.
+func NewTenEnvForTest(extensionName string) TenEnv {
+	return newTenEnv(extensionName)
+}
+
+func (te *tenEnvImpl) Log(level LogLevel, msg string, category *string, fields *Value, extra *Value) error {
+	record := &LogRecord{
+		Level:         level,
+		Message:       msg,
+		ExtensionName: te.extensionName,
+	}
+	if category != nil {
+		record.Category = *category
+	}
+	if fields != nil {
+		record.Fields = *fields
+	}
+	return dispatchLog(record)
+}
+
+func (te *tenEnvImpl) LogDebug(msg string) { _ = te.Log(LogLevelDebug, msg, nil, nil, nil) }
+func (te *tenEnvImpl) LogInfo(msg string)  { _ = te.Log(LogLevelInfo, msg, nil, nil, nil) }
+func (te *tenEnvImpl) LogError(msg string) { _ = te.Log(LogLevelError, msg, nil, nil, nil) }
+
+func (te *tenEnvImpl) SetProperty(path string, v interface{}) error {
+	return setPropertyOnStore(te.store, path, v)
+}
+
+// setPropertyOnStore holds the encode-and-store logic shared by
+// tenEnvImpl.SetProperty and tenEnvTesterImpl.SetProperty, so a standalone
+// extension test can populate the same kind of property store a real
+// TenEnv would before calling ValidateProperties.
+func setPropertyOnStore(store *propertyStore, path string, v interface{}) error {
+	switch x := v.(type) {
+	case bool:
+		store.set(path, NewBoolValue(x))
+		incPropertyOp("set", "bool")
+		return nil
+	case int:
+		store.set(path, NewIntValue(x))
+		incPropertyOp("set", "int")
+		return nil
+	case int8:
+		store.set(path, NewInt8Value(x))
+		incPropertyOp("set", "int8")
+		return nil
+	case int16:
+		store.set(path, NewInt16Value(x))
+		incPropertyOp("set", "int16")
+		return nil
+	case int32:
+		store.set(path, NewInt32Value(x))
+		incPropertyOp("set", "int32")
+		return nil
+	case int64:
+		store.set(path, NewInt64Value(x))
+		incPropertyOp("set", "int64")
+		return nil
+	case uint:
+		store.set(path, NewUint64Value(uint64(x)))
+		incPropertyOp("set", "uint")
+		return nil
+	case uint8:
+		store.set(path, NewUint8Value(x))
+		incPropertyOp("set", "uint8")
+		return nil
+	case uint16:
+		store.set(path, NewUint16Value(x))
+		incPropertyOp("set", "uint16")
+		return nil
+	case uint32:
+		store.set(path, NewUint32Value(x))
+		incPropertyOp("set", "uint32")
+		return nil
+	case uint64:
+		store.set(path, NewUint64Value(x))
+		incPropertyOp("set", "uint64")
+		return nil
+	case float32:
+		store.set(path, NewFloat32Value(x))
+		incPropertyOp("set", "float32")
+		return nil
+	case float64:
+		store.set(path, NewFloat64Value(x))
+		incPropertyOp("set", "float64")
+		return nil
+	case string:
+		store.set(path, NewStringValue(x))
+		incPropertyOp("set", "string")
+		return nil
+	case []byte:
+		store.set(path, NewBufValue(x))
+		incPropertyOp("set", "buf")
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return NewTenError(ErrorCodeGeneric, fmt.Sprintf("unsupported property type %T", v))
+		}
+		encoded, err := encodeStructValue(rv, 0, defaultMaxStructDepth, map[uintptr]bool{})
+		if err != nil {
+			return NewTenError(ErrorCodeGeneric, err.Error())
+		}
+		store.set(path, encoded)
+		store.setPtr(path, v)
+		incPropertyOp("set", "struct_ptr")
+		return nil
+	case reflect.Struct, reflect.Map, reflect.Slice:
+		encoded, err := encodeStructValue(rv, 0, defaultMaxStructDepth, map[uintptr]bool{})
+		if err != nil {
+			return NewTenError(ErrorCodeGeneric, err.Error())
+		}
+		store.set(path, encoded)
+		incPropertyOp("set", rv.Kind().String())
+		return nil
+	}
+
+	return NewTenError(ErrorCodeGeneric, fmt.Sprintf("unsupported property type %T", v))
+}
+
+func (te *tenEnvImpl) getValue(path string) (Value, error) {
+	v, ok := te.store.get(path)
+	if !ok {
+		return nil, NewTenError(ErrorCodeGeneric, fmt.Sprintf("property %q not found", path))
+	}
+	return v, nil
+}
+
+func (te *tenEnvImpl) GetPropertyBool(path string) (bool, error) {
+	v, err := te.getValue(path)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.Interface().(bool)
+	if !ok {
+		return false, NewTenError(ErrorCodeGeneric, fmt.Sprintf("property %q is not a bool", path))
+	}
+	incPropertyOp("get", "bool")
+	return b, nil
+}
+
+func (te *tenEnvImpl) GetPropertyString(path string) (string, error) {
+	v, err := te.getValue(path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.Interface().(string)
+	if !ok {
+		return "", NewTenError(ErrorCodeGeneric, fmt.Sprintf("property %q is not a string", path))
+	}
+	incPropertyOp("get", "string")
+	return s, nil
+}
+
+func (te *tenEnvImpl) GetPropertyBytes(path string) ([]byte, error) {
+	v, err := te.getValue(path)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.Interface().([]byte)
+	if !ok {
+		return nil, NewTenError(ErrorCodeGeneric, fmt.Sprintf("property %q is not a buf", path))
+	}
+	incPropertyOp("get", "buf")
+	return b, nil
+}
+
+func (te *tenEnvImpl) GetPropertyPtr(path string) (interface{}, error) {
+	v, ok := te.store.getPtr(path)
+	if !ok {
+		return nil, NewTenError(ErrorCodeGeneric, fmt.Sprintf("property %q was not set as a pointer", path))
+	}
+	incPropertyOp("get", "ptr")
+	return v, nil
+}
+
+// GetPropertyAs populates out (a non-nil pointer) from the property at
+// path via reflection, the inverse of the struct-walking SetProperty
+// does for structs/maps/slices.
+func (te *tenEnvImpl) GetPropertyAs(path string, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return NewTenError(ErrorCodeGeneric, "GetPropertyAs requires a non-nil pointer")
+	}
+	v, err := te.getValue(path)
+	if err != nil {
+		return err
+	}
+	if decErr := decodeStructValue(v, rv.Elem()); decErr != nil {
+		return NewTenError(ErrorCodeGeneric, decErr.Error())
+	}
+	incPropertyOp("get", "struct_ptr")
+	return nil
+}
+
+func (te *tenEnvImpl) getInt(path string) (int64, error) {
+	v, err := te.getValue(path)
+	if err != nil {
+		return 0, err
+	}
+	n, coerceErr := coerceToInt64(v.Interface())
+	if coerceErr != nil {
+		return 0, NewTenError(ErrorCodeGeneric, coerceErr.Error())
+	}
+	incPropertyOp("get", "int")
+	return n, nil
+}
+
+func (te *tenEnvImpl) GetPropertyInt8(path string) (int8, error) {
+	n, err := te.getInt(path)
+	return int8(n), err
+}
+
+func (te *tenEnvImpl) GetPropertyInt16(path string) (int16, error) {
+	n, err := te.getInt(path)
+	return int16(n), err
+}
+
+func (te *tenEnvImpl) GetPropertyInt32(path string) (int32, error) {
+	n, err := te.getInt(path)
+	return int32(n), err
+}
+
+func (te *tenEnvImpl) GetPropertyInt64(path string) (int64, error) {
+	return te.getInt(path)
+}
+
+func (te *tenEnvImpl) getUint(path string) (uint64, error) {
+	v, err := te.getValue(path)
+	if err != nil {
+		return 0, err
+	}
+	n, coerceErr := coerceToUint64(v.Interface())
+	if coerceErr != nil {
+		return 0, NewTenError(ErrorCodeGeneric, coerceErr.Error())
+	}
+	incPropertyOp("get", "uint")
+	return n, nil
+}
+
+func (te *tenEnvImpl) GetPropertyUint32(path string) (uint32, error) {
+	n, err := te.getUint(path)
+	return uint32(n), err
+}
+
+func (te *tenEnvImpl) GetPropertyUint64(path string) (uint64, error) {
+	return te.getUint(path)
+}
+
+func (te *tenEnvImpl) getFloat(path string) (float64, error) {
+	v, err := te.getValue(path)
+	if err != nil {
+		return 0, err
+	}
+	n, coerceErr := coerceToFloat64(v.Interface())
+	if coerceErr != nil {
+		return 0, NewTenError(ErrorCodeGeneric, coerceErr.Error())
+	}
+	incPropertyOp("get", "float")
+	return n, nil
+}
+
+func (te *tenEnvImpl) GetPropertyFloat32(path string) (float32, error) {
+	n, err := te.getFloat(path)
+	return float32(n), err
+}
+
+func (te *tenEnvImpl) GetPropertyFloat64(path string) (float64, error) {
+	return te.getFloat(path)
+}
+
+func (te *tenEnvImpl) GetPropertyToJSONBytes(path string) ([]byte, error) {
+	if path == "" {
+		return valueToJSONBytes(NewObjectValue(te.store.snapshot()))
+	}
+	v, err := te.getValue(path)
+	if err != nil {
+		return nil, err
+	}
+	return valueToJSONBytes(v)
+}
+
+func (te *tenEnvImpl) Counter(name string, labelPairs ...string) Counter {
+	return defaultMetrics.counter(name, labelPairs...)
+}
+
+func (te *tenEnvImpl) ValidateProperties() error {
+	schema, ok := getPropertySchema(te.extensionName)
+	if !ok {
+		return nil
+	}
+	return schema.Validate(te.store.snapshot())
+}
+
+func (te *tenEnvImpl) OnInitDone() {}
+func (te *tenEnvImpl) OnStopDone() {}
+
+func (te *tenEnvImpl) ReturnResult(result CmdResult, cmd Cmd) error {
+	return nil
+}
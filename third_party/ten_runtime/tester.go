@@ -0,0 +1,108 @@
+package ten
+
+import "fmt"
+
+// TenEnvTester is the handle an ExtensionTester uses to talk back to the
+// test harness: logging, property access, metrics, and start/stop/result
+// reporting.
+type TenEnvTester interface {
+	Log(level LogLevel, msg string, category *string, fields *Value, extra *Value) error
+	LogInfo(msg string)
+
+	// SetProperty stores v under path in the tester's own property store,
+	// using the same encoding SetProperty on a real TenEnv uses, so a test
+	// can stage the properties it wants ValidateProperties to check.
+	SetProperty(path string, v interface{}) error
+
+	// Counter returns a Counter scoped to name and the given label pairs,
+	// backed by the same process-wide metrics registry TenEnv.Counter
+	// uses, so a test's own activity (cmd counts, timings) shows up
+	// alongside the extension-under-test's metrics.
+	Counter(name string, labelPairs ...string) Counter
+
+	// ValidateProperties checks every property staged via SetProperty
+	// against the JSON Schema registered under this tester's name via
+	// RegisterPropertySchema, if any. It is a no-op if no schema was
+	// registered.
+	ValidateProperties() error
+
+	OnStartDone()
+	OnStopDone()
+	ReturnResult(result CmdResult, cmd Cmd) error
+	StopTest(err *TenError) error
+}
+
+// ExtensionTester is the lifecycle interface a standalone extension test
+// implements.
+type ExtensionTester interface {
+	OnStart(tenEnvTester TenEnvTester)
+	OnStop(tenEnvTester TenEnvTester)
+	OnCmd(tenEnvTester TenEnvTester, cmd Cmd)
+}
+
+// DefaultExtensionTester provides no-op implementations of every
+// ExtensionTester method, so concrete testers only need to override the
+// ones they care about.
+type DefaultExtensionTester struct{}
+
+func (DefaultExtensionTester) OnStart(tenEnvTester TenEnvTester)        { tenEnvTester.OnStartDone() }
+func (DefaultExtensionTester) OnStop(tenEnvTester TenEnvTester)         { tenEnvTester.OnStopDone() }
+func (DefaultExtensionTester) OnCmd(tenEnvTester TenEnvTester, cmd Cmd) {}
+
+type tenEnvTesterImpl struct {
+	name    string
+	store   *propertyStore
+	stopped bool
+	result  *TenError
+}
+
+// newTenEnvTester builds a TenEnvTester for a standalone extension test
+// named name.
+func newTenEnvTester(name string) *tenEnvTesterImpl {
+	return &tenEnvTesterImpl{name: name, store: newPropertyStore()}
+}
+
+func (te *tenEnvTesterImpl) Log(level LogLevel, msg string, category *string, fields *Value, extra *Value) error {
+	record := &LogRecord{Level: level, Message: msg, ExtensionName: te.name}
+	if category != nil {
+		record.Category = *category
+	}
+	if fields != nil {
+		record.Fields = *fields
+	}
+	return dispatchLog(record)
+}
+
+func (te *tenEnvTesterImpl) LogInfo(msg string) { _ = te.Log(LogLevelInfo, msg, nil, nil, nil) }
+
+func (te *tenEnvTesterImpl) SetProperty(path string, v interface{}) error {
+	return setPropertyOnStore(te.store, path, v)
+}
+
+func (te *tenEnvTesterImpl) Counter(name string, labelPairs ...string) Counter {
+	return defaultMetrics.counter(name, labelPairs...)
+}
+
+func (te *tenEnvTesterImpl) ValidateProperties() error {
+	schema, ok := getPropertySchema(te.name)
+	if !ok {
+		return nil
+	}
+	return schema.Validate(te.store.snapshot())
+}
+
+func (te *tenEnvTesterImpl) OnStartDone() {}
+func (te *tenEnvTesterImpl) OnStopDone()  {}
+
+func (te *tenEnvTesterImpl) ReturnResult(result CmdResult, cmd Cmd) error {
+	return nil
+}
+
+func (te *tenEnvTesterImpl) StopTest(err *TenError) error {
+	if te.stopped {
+		return NewTenError(ErrorCodeGeneric, fmt.Sprintf("test %q already stopped", te.name))
+	}
+	te.stopped = true
+	te.result = err
+	return nil
+}
@@ -0,0 +1,50 @@
+package ten
+
+import "testing"
+
+func TestTenEnvTesterCounterSharesProcessRegistry(t *testing.T) {
+	te := newTenEnvTester("tester_test_extension")
+	te.Counter("tester_test_counter_total", "op", "set").Inc()
+
+	snap := MetricsSnapshot()
+	if snap["tester_test_counter_total,op=set"] != 1 {
+		t.Fatalf("expected tester counter to land in MetricsSnapshot, got %+v", snap)
+	}
+}
+
+func TestTenEnvTesterValidatePropertiesAgainstRegisteredSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"expected_greeting_msg": {"type": "string"},
+			"delay_ms": {"type": "integer", "minimum": 0}
+		},
+		"required": ["expected_greeting_msg", "delay_ms"]
+	}`)
+	if err := RegisterPropertySchema("tester_test_tester", schema); err != nil {
+		t.Fatalf("RegisterPropertySchema: %v", err)
+	}
+
+	te := newTenEnvTester("tester_test_tester")
+	if err := te.SetProperty("expected_greeting_msg", "hello"); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+	if err := te.SetProperty("delay_ms", uint32(10)); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+	if err := te.ValidateProperties(); err != nil {
+		t.Fatalf("ValidateProperties: %v", err)
+	}
+
+	if err := te.SetProperty("delay_ms", int64(-1)); err != nil {
+		t.Fatalf("SetProperty: %v", err)
+	}
+	err := te.ValidateProperties()
+	if err == nil {
+		t.Fatalf("expected ValidateProperties to fail for a negative delay_ms")
+	}
+	tenErr, ok := err.(*TenError)
+	if !ok || tenErr.Code != ErrorCodeInvalidProperty {
+		t.Fatalf("expected ErrorCodeInvalidProperty, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package ten
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// ValueKind identifies the concrete type carried by a Value.
+type ValueKind int
+
+const (
+	ValueKindInvalid ValueKind = iota
+	ValueKindBool
+	ValueKindInt64
+	ValueKindUint64
+	ValueKindFloat64
+	ValueKindString
+	ValueKindJSONString
+	ValueKindBuf
+	ValueKindArray
+	ValueKindObject
+)
+
+// Value is the runtime's dynamically-typed field/property value. Integers
+// of every width collapse to Int64/Uint64 storage, matching the existing
+// fixed-width getters' (GetPropertyInt8, GetPropertyUint32, ...) loose
+// coercion behavior.
+type Value interface {
+	Kind() ValueKind
+	Interface() interface{}
+}
+
+type value struct {
+	kind ValueKind
+	v    interface{}
+}
+
+func (val *value) Kind() ValueKind        { return val.kind }
+func (val *value) Interface() interface{} { return val.v }
+
+func NewBoolValue(v bool) Value         { return &value{ValueKindBool, v} }
+func NewIntValue(v int) Value           { return &value{ValueKindInt64, int64(v)} }
+func NewInt8Value(v int8) Value         { return &value{ValueKindInt64, int64(v)} }
+func NewInt16Value(v int16) Value       { return &value{ValueKindInt64, int64(v)} }
+func NewInt32Value(v int32) Value       { return &value{ValueKindInt64, int64(v)} }
+func NewInt64Value(v int64) Value       { return &value{ValueKindInt64, v} }
+func NewUint8Value(v uint8) Value       { return &value{ValueKindUint64, uint64(v)} }
+func NewUint16Value(v uint16) Value     { return &value{ValueKindUint64, uint64(v)} }
+func NewUint32Value(v uint32) Value     { return &value{ValueKindUint64, uint64(v)} }
+func NewUint64Value(v uint64) Value     { return &value{ValueKindUint64, v} }
+func NewFloat32Value(v float32) Value   { return &value{ValueKindFloat64, float64(v)} }
+func NewFloat64Value(v float64) Value   { return &value{ValueKindFloat64, v} }
+func NewStringValue(v string) Value     { return &value{ValueKindString, v} }
+func NewJSONStringValue(v string) Value { return &value{ValueKindJSONString, v} }
+func NewBufValue(v []byte) Value        { return &value{ValueKindBuf, v} }
+func NewArrayValue(v []Value) Value     { return &value{ValueKindArray, v} }
+
+func NewObjectValue(v map[string]Value) Value {
+	if v == nil {
+		v = map[string]Value{}
+	}
+	return &value{ValueKindObject, v}
+}
+
+// valueToJSONInterface converts a Value tree into plain interface{} data
+// suitable for encoding/json: bufs become base64 strings and embedded
+// JSON strings are inlined verbatim rather than double-encoded.
+func valueToJSONInterface(v Value) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch v.Kind() {
+	case ValueKindBuf:
+		return base64.StdEncoding.EncodeToString(v.Interface().([]byte)), nil
+	case ValueKindJSONString:
+		return json.RawMessage(v.Interface().(string)), nil
+	case ValueKindArray:
+		items := v.Interface().([]Value)
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			converted, err := valueToJSONInterface(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = converted
+		}
+		return out, nil
+	case ValueKindObject:
+		obj := v.Interface().(map[string]Value)
+		out := make(map[string]interface{}, len(obj))
+		for k, item := range obj {
+			converted, err := valueToJSONInterface(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = converted
+		}
+		return out, nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// ValueToJSON exports valueToJSONInterface for callers outside this
+// package that need to bridge a Value tree (e.g. a LogRecord's Fields)
+// into plain interface{} data of their own, such as
+// ten_runtime_go/app's log bridge turning it into a log.Record.Fields map.
+func ValueToJSON(v Value) (interface{}, error) {
+	return valueToJSONInterface(v)
+}
+
+func valueToJSONBytes(v Value) ([]byte, error) {
+	data, err := valueToJSONInterface(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
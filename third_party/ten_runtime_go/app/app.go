@@ -0,0 +1,152 @@
+// Package app provides the App entry point consumed by TEN Go apps:
+// log handler registration and the Run loop that dispatches log
+// records to every registered handler off of a bounded queue.
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/TEN-framework/ten_runtime_go/pkg/log"
+	"github.com/TEN-framework/ten_runtime_go/pkg/metrics"
+)
+
+// defaultMetricsMaxSeries bounds the cardinality of the registry an App
+// creates via EnableMetrics.
+const defaultMetricsMaxSeries = 10000
+
+// recordQueueSize bounds how many pending log records App will buffer
+// before it starts dropping the oldest one to keep publishing
+// non-blocking for callers on the hot path.
+const recordQueueSize = 1024
+
+// App is the top-level handle for a TEN Go application: it owns the
+// registered log handlers and the dispatch loop that fans records out
+// to them.
+type App struct {
+	name    string
+	version string
+
+	mu       sync.Mutex
+	handlers []log.Handler
+
+	recordCh chan log.Record
+	dropped  uint64
+
+	metricsAddr string
+	metricsPath string
+	registry    *metrics.Registry
+}
+
+// NewApp builds an App identified by name/version.
+func NewApp(name, version string) *App {
+	return &App{
+		name:     name,
+		version:  version,
+		recordCh: make(chan log.Record, recordQueueSize),
+	}
+}
+
+// AddLogHandler registers h to receive every record published via
+// PublishLogRecord once Run's dispatch loop is running.
+func (a *App) AddLogHandler(h log.Handler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.handlers = append(a.handlers, h)
+}
+
+// EnableMetrics turns on the Prometheus/OpenMetrics exporter: Run will
+// serve a's metrics registry at http://addr/path until ctx is cancelled.
+// addr must not be empty. Metrics returns the registry to record against.
+func (a *App) EnableMetrics(addr, path string) error {
+	if addr == "" {
+		return fmt.Errorf("app: EnableMetrics requires a non-empty addr")
+	}
+	if path == "" {
+		path = "/metrics"
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.metricsAddr = addr
+	a.metricsPath = path
+	a.registry = metrics.NewRegistry(defaultMetricsMaxSeries)
+	return nil
+}
+
+// Metrics returns the registry EnableMetrics created, or nil if metrics
+// were never enabled.
+func (a *App) Metrics() *metrics.Registry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.registry
+}
+
+// DroppedLogRecords reports how many log records were discarded because
+// the internal queue was full.
+func (a *App) DroppedLogRecords() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// PublishLogRecord enqueues record for dispatch to every registered
+// handler. If the queue is full, the oldest queued record is dropped to
+// make room, and DroppedLogRecords is incremented.
+func (a *App) PublishLogRecord(record log.Record) {
+	for {
+		select {
+		case a.recordCh <- record:
+			return
+		default:
+		}
+
+		select {
+		case <-a.recordCh:
+			atomic.AddUint64(&a.dropped, 1)
+		default:
+		}
+	}
+}
+
+// Run starts the log dispatch loop (and, if EnableMetrics was called,
+// the metrics exporter), blocking until ctx is cancelled.
+func (a *App) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.dispatchLoop(ctx)
+	}()
+
+	a.mu.Lock()
+	addr, path, reg := a.metricsAddr, a.metricsPath, a.registry
+	a.mu.Unlock()
+
+	if reg != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = metrics.Serve(ctx, addr, path, reg)
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (a *App) dispatchLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case record := <-a.recordCh:
+			a.mu.Lock()
+			handlers := append([]log.Handler(nil), a.handlers...)
+			a.mu.Unlock()
+			for _, h := range handlers {
+				_ = h.Handle(record)
+			}
+		}
+	}
+}
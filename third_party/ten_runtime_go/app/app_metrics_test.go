@@ -0,0 +1,23 @@
+package app
+
+import "testing"
+
+func TestEnableMetricsRejectsEmptyAddr(t *testing.T) {
+	a := NewApp("test_app", "0.0.1")
+	if err := a.EnableMetrics("", "/metrics"); err == nil {
+		t.Fatalf("expected error for empty addr")
+	}
+	if a.Metrics() != nil {
+		t.Fatalf("expected no registry after a rejected EnableMetrics call")
+	}
+}
+
+func TestEnableMetricsCreatesRegistry(t *testing.T) {
+	a := NewApp("test_app", "0.0.1")
+	if err := a.EnableMetrics(":9464", ""); err != nil {
+		t.Fatalf("EnableMetrics: %v", err)
+	}
+	if a.Metrics() == nil {
+		t.Fatalf("expected a registry after EnableMetrics")
+	}
+}
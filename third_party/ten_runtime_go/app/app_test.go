@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TEN-framework/ten_runtime_go/pkg/log"
+)
+
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []log.Record
+}
+
+func (h *recordingHandler) Handle(r log.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) Level() log.Level { return log.DebugLevel }
+
+func (h *recordingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.records)
+}
+
+func TestAppDispatchesPublishedRecords(t *testing.T) {
+	a := NewApp("test_app", "0.0.1")
+	h := &recordingHandler{}
+	a.AddLogHandler(h)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.Run(ctx)
+
+	a.PublishLogRecord(log.Record{Level: log.InfoLevel, Message: "hello"})
+
+	deadline := time.Now().Add(time.Second)
+	for h.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if h.count() != 1 {
+		t.Fatalf("expected handler to receive 1 record, got %d", h.count())
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"time"
+
+	ten "ten_framework/ten_runtime"
+
+	"github.com/TEN-framework/ten_runtime_go/pkg/log"
+)
+
+// BridgeTenRuntime connects a ten_framework/ten_runtime extension's
+// TenEnv.Log/RegisterLogHook pipeline and its process-wide metrics to
+// this App: every LogRecord that survives the ten package's hook chain
+// is translated into a log.Record and published through a's own
+// dispatch loop (so it reaches every handler added via AddLogHandler),
+// and, if EnableMetrics was called before it, ten.MetricsSnapshot is
+// merged into a's own registry on every scrape. Call it once, after
+// EnableMetrics and before Run, from any
+// process that loads both this App and ten_framework/ten_runtime
+// extensions.
+func (a *App) BridgeTenRuntime() {
+	ten.RegisterLogSink(func(record *ten.LogRecord) {
+		a.PublishLogRecord(tenLogRecordToLogRecord(record))
+	})
+
+	a.mu.Lock()
+	reg := a.registry
+	a.mu.Unlock()
+	if reg != nil {
+		reg.SetPreRenderHook(func() {
+			reg.ImportExternal(ten.MetricsSnapshot())
+		})
+	}
+}
+
+func tenLogRecordToLogRecord(record *ten.LogRecord) log.Record {
+	return log.Record{
+		Level:         log.Level(record.Level),
+		Time:          time.Now(),
+		Category:      record.Category,
+		Message:       record.Message,
+		Fields:        tenFieldsToMap(record.Fields),
+		ExtensionName: record.ExtensionName,
+		GraphID:       record.GraphID,
+	}
+}
+
+// tenFieldsToMap faithfully converts a ten.Value field tree (as built by
+// ten.NewObjectValue) into the plain map[string]interface{} log.Record
+// expects: ints/floats as numbers, bufs as base64, nested maps/slices
+// preserved, via the same conversion ten.GetPropertyToJSONBytes uses.
+func tenFieldsToMap(fields ten.Value) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	converted, err := ten.ValueToJSON(fields)
+	if err != nil {
+		return nil
+	}
+	m, _ := converted.(map[string]interface{})
+	return m
+}
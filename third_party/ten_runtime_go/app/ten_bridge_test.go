@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	ten "ten_framework/ten_runtime"
+)
+
+func TestBridgeTenRuntimeForwardsLogRecords(t *testing.T) {
+	a := NewApp("bridge_test_app", "0.0.1")
+	h := &recordingHandler{}
+	a.AddLogHandler(h)
+	a.BridgeTenRuntime()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.Run(ctx)
+
+	tenEnv := ten.NewTenEnvForTest("bridge_test_extension")
+	fields := ten.NewObjectValue(map[string]ten.Value{
+		"count": ten.NewIntValue(3),
+		"raw":   ten.NewBufValue([]byte("hi")),
+	})
+	category := "bridge"
+	if err := tenEnv.Log(ten.LogLevelInfo, "hello from ten", &category, &fields, nil); err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for h.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if h.count() != 1 {
+		t.Fatalf("expected the bridge to publish 1 record, got %d", h.count())
+	}
+
+	got := h.records[0]
+	if got.Message != "hello from ten" || got.Category != "bridge" {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+	if got.Fields["count"] != int64(3) {
+		t.Fatalf("expected count field to survive the bridge as an int64, got %+v", got.Fields["count"])
+	}
+	if got.Fields["raw"] != "aGk=" {
+		t.Fatalf("expected raw buf field to be base64-encoded, got %+v", got.Fields["raw"])
+	}
+}
+
+func TestBridgeTenRuntimeImportsMetricsIntoScrape(t *testing.T) {
+	a := NewApp("bridge_metrics_test_app", "0.0.1")
+	if err := a.EnableMetrics(":0", "/metrics"); err != nil {
+		t.Fatalf("EnableMetrics: %v", err)
+	}
+	a.BridgeTenRuntime()
+
+	tenEnv := ten.NewTenEnvForTest("bridge_metrics_test_extension")
+	tenEnv.Counter("bridge_test_counter_total", "op", "set").Inc()
+
+	var buf bytes.Buffer
+	if err := a.Metrics().WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+	if !strings.Contains(buf.String(), "bridge_test_counter_total") {
+		t.Fatalf("expected the ten package's counter to appear in the scrape, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"fmt"
+	"os"
+)
+
+// ConsoleHandler writes records to stdout, one line per record, in
+// plain or ANSI-colored form.
+type ConsoleHandler struct {
+	cfg *Config
+}
+
+// NewConsoleHandler builds a ConsoleHandler from cfg.
+func NewConsoleHandler(cfg *Config) *ConsoleHandler {
+	return &ConsoleHandler{cfg: cfg}
+}
+
+func (h *ConsoleHandler) Level() Level { return h.cfg.Level }
+
+func (h *ConsoleHandler) Handle(record Record) error {
+	if record.Level < h.cfg.Level {
+		return nil
+	}
+	line := fmt.Sprintf("[%s] %s", record.Level, record.Message)
+	if record.Category != "" {
+		line += " category=" + record.Category
+	}
+	if record.ExtensionName != "" {
+		line += " extension=" + record.ExtensionName
+	}
+	if h.cfg.Color {
+		line = colorize(record.Level, line)
+	}
+	_, err := fmt.Fprintln(os.Stdout, line)
+	return err
+}
+
+func colorize(level Level, s string) string {
+	code := "0"
+	switch level {
+	case WarnLevel:
+		code = "33"
+	case ErrorLevel, FatalLevel:
+		code = "31"
+	case DebugLevel, VerboseLevel:
+		code = "90"
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
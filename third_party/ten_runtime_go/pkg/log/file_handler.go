@@ -0,0 +1,151 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileHandlerOptions configures a FileHandler: size-based rotation
+// (MaxSizeMB), gzip of rotated files (Compress), and age-based pruning of
+// rotated files (MaxAgeDays). Files are rotated on size only, never on a
+// time schedule.
+type FileHandlerOptions struct {
+	Path       string
+	MaxSizeMB  int
+	MaxAgeDays int
+	Compress   bool
+}
+
+// FileHandler appends records as plain text lines to Path, rotating (and
+// optionally gzip-ing) the file once it exceeds MaxSizeMB, and pruning
+// rotated files older than MaxAgeDays.
+type FileHandler struct {
+	cfg  *Config
+	opts FileHandlerOptions
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// NewFileHandler builds a FileHandler from cfg and opts. The file is
+// opened lazily, on the first Handle call.
+func NewFileHandler(cfg *Config, opts FileHandlerOptions) *FileHandler {
+	return &FileHandler{cfg: cfg, opts: opts}
+}
+
+func (h *FileHandler) Level() Level { return h.cfg.Level }
+
+func (h *FileHandler) Handle(record Record) error {
+	if record.Level < h.cfg.Level {
+		return nil
+	}
+	line := fmt.Sprintf("%s [%s] %s\n", record.Time.Format(time.RFC3339Nano), record.Level, record.Message)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.ensureOpen(); err != nil {
+		return err
+	}
+	if h.opts.MaxSizeMB > 0 && h.size+int64(len(line)) > int64(h.opts.MaxSizeMB)*1024*1024 {
+		if err := h.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := h.f.WriteString(line)
+	h.size += int64(n)
+	return err
+}
+
+func (h *FileHandler) ensureOpen() error {
+	if h.f != nil {
+		return nil
+	}
+	if dir := filepath.Dir(h.opts.Path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(h.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	h.f = f
+	h.size = info.Size()
+	return nil
+}
+
+func (h *FileHandler) rotate() error {
+	if err := h.f.Close(); err != nil {
+		return err
+	}
+	h.f = nil
+	h.size = 0
+
+	rotated := fmt.Sprintf("%s.%s", h.opts.Path, time.Now().UTC().Format("20060102T150405"))
+	if err := os.Rename(h.opts.Path, rotated); err != nil {
+		return err
+	}
+	if h.opts.Compress {
+		if err := gzipAndRemove(rotated); err != nil {
+			return err
+		}
+	}
+	h.pruneOldRotations()
+	return h.ensureOpen()
+}
+
+func gzipAndRemove(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".gz", buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (h *FileHandler) pruneOldRotations() {
+	if h.opts.MaxAgeDays <= 0 {
+		return
+	}
+	dir := filepath.Dir(h.opts.Path)
+	base := filepath.Base(h.opts.Path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-time.Duration(h.opts.MaxAgeDays) * 24 * time.Hour)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), base+".") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		_ = os.Remove(filepath.Join(dir, e.Name()))
+	}
+}
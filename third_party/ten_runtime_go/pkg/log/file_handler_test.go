@@ -0,0 +1,67 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileHandlerRotatesOnSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agent.log")
+
+	h := NewFileHandler(&Config{Level: InfoLevel}, FileHandlerOptions{
+		Path:      path,
+		MaxSizeMB: 1,
+	})
+
+	record := Record{Level: InfoLevel, Time: time.Now(), Message: "hello"}
+	if err := h.Handle(record); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	// Mark the open file as already at MaxSizeMB instead of writing 1MB of
+	// records, so the next record must rotate it first.
+	h.size = 1024 * 1024
+	if err := h.Handle(record); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	rotated := 0
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "agent.log.") {
+			rotated++
+		}
+	}
+	if rotated != 1 {
+		t.Fatalf("expected 1 rotated file, got %d", rotated)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatalf("expected file content, got empty file")
+	}
+}
+
+func TestFileHandlerRespectsLevelFilter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agent.log")
+
+	h := NewFileHandler(&Config{Level: WarnLevel}, FileHandlerOptions{Path: path, MaxSizeMB: 1})
+	if err := h.Handle(Record{Level: DebugLevel, Message: "dropped"}); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no file to be created for a filtered-out record, stat err=%v", err)
+	}
+}
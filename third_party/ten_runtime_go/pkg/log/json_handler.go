@@ -0,0 +1,51 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONHandler emits one JSON line per record, with all field values
+// faithfully serialized (ints/floats as numbers, bufs as base64 via the
+// caller, nested maps/slices preserved).
+type JSONHandler struct {
+	cfg *Config
+	w   io.Writer
+	mu  sync.Mutex
+}
+
+// NewJSONHandler builds a JSONHandler writing newline-delimited JSON to w.
+func NewJSONHandler(cfg *Config, w io.Writer) *JSONHandler {
+	return &JSONHandler{cfg: cfg, w: w}
+}
+
+func (h *JSONHandler) Level() Level { return h.cfg.Level }
+
+func (h *JSONHandler) Handle(record Record) error {
+	if record.Level < h.cfg.Level {
+		return nil
+	}
+
+	line := map[string]interface{}{
+		"level":     record.Level.String(),
+		"time":      record.Time.Format(time.RFC3339Nano),
+		"message":   record.Message,
+		"category":  record.Category,
+		"extension": record.ExtensionName,
+		"graph_id":  record.GraphID,
+		"fields":    filterFields(record.Fields, h.cfg.AllowFields, h.cfg.DenyFields),
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.w.Write(data)
+	return err
+}
@@ -0,0 +1,131 @@
+// Package log provides the pluggable log handler ecosystem consumed via
+// app.AddLogHandler: a Handler interface plus Console, JSON, rotating
+// File, and Syslog implementations, each with its own level filter. Only
+// the JSON handler writes record fields, and only it applies the
+// Config's field-key allow/deny lists.
+package log
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Level mirrors the runtime's log severities.
+type Level int
+
+const (
+	VerboseLevel Level = iota
+	DebugLevel
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	FatalLevel
+)
+
+func (l Level) String() string {
+	switch l {
+	case VerboseLevel:
+		return "verbose"
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	case FatalLevel:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// Config is shared by every handler constructor. AllowFields/DenyFields
+// are key globs ("*" matches any run of characters); Deny takes
+// precedence over Allow, and a nil list means no restriction.
+type Config struct {
+	Level       Level
+	Format      string
+	Color       bool
+	AllowFields []string
+	DenyFields  []string
+}
+
+// NewConfig returns a Config defaulting to info level, plain format.
+func NewConfig() *Config {
+	return &Config{Level: InfoLevel, Format: "plain"}
+}
+
+// Record is the full record handed to a Handler: level, time, category,
+// message, fields, and the extension/graph it came from.
+type Record struct {
+	Level         Level
+	Time          time.Time
+	Category      string
+	Message       string
+	Fields        map[string]interface{}
+	ExtensionName string
+	GraphID       string
+}
+
+// Handler receives every Record whose level passes its own filter.
+type Handler interface {
+	Handle(record Record) error
+	Level() Level
+}
+
+func filterFields(fields map[string]interface{}, allow, deny []string) map[string]interface{} {
+	if len(fields) == 0 || (len(allow) == 0 && len(deny) == 0) {
+		return fields
+	}
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if len(allow) > 0 && !matchesAny(allow, k) {
+			continue
+		}
+		if matchesAny(deny, k) {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func matchesAny(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGlob(pattern, s string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == s
+	}
+	re := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matched, _ := regexp.MatchString(re, s)
+	return matched
+}
+
+// Info prints msg at info level to the default console sink, independent
+// of any handlers registered via app.AddLogHandler.
+func Info(args ...interface{}) { printDefault(InfoLevel, args...) }
+
+// Error prints msg at error level to the default console sink.
+func Error(args ...interface{}) { printDefault(ErrorLevel, args...) }
+
+// Debug prints msg at debug level to the default console sink.
+func Debug(args ...interface{}) { printDefault(DebugLevel, args...) }
+
+// Warn prints msg at warn level to the default console sink.
+func Warn(args ...interface{}) { printDefault(WarnLevel, args...) }
+
+func printDefault(level Level, args ...interface{}) {
+	fmt.Fprintf(os.Stdout, "[%s] %s\n", level, fmt.Sprint(args...))
+}
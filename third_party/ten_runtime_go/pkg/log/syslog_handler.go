@@ -0,0 +1,76 @@
+package log
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// SyslogOptions configures a SyslogHandler's destination and minimum
+// level (distinct from cfg.Level, to match a handler that still wants to
+// receive e.g. debug records for MinLevel-based filtering logic).
+type SyslogOptions struct {
+	Network  string
+	Addr     string
+	Tag      string
+	MinLevel Level
+}
+
+// SyslogHandler ships records as RFC5424 syslog messages over a
+// connection dialed from Network/Addr.
+type SyslogHandler struct {
+	cfg  *Config
+	opts SyslogOptions
+	conn net.Conn
+	mu   sync.Mutex
+}
+
+// NewSyslogHandler dials opts.Network/opts.Addr and returns a
+// SyslogHandler writing to that connection.
+func NewSyslogHandler(cfg *Config, opts SyslogOptions) (*SyslogHandler, error) {
+	conn, err := net.Dial(opts.Network, opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("log: dial syslog at %s://%s: %w", opts.Network, opts.Addr, err)
+	}
+	return &SyslogHandler{cfg: cfg, opts: opts, conn: conn}, nil
+}
+
+func (h *SyslogHandler) Level() Level { return h.cfg.Level }
+
+func (h *SyslogHandler) Handle(record Record) error {
+	if record.Level < h.opts.MinLevel {
+		return nil
+	}
+
+	const facilityUser = 1
+	priority := facilityUser*8 + syslogSeverity(record.Level)
+	ts := record.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	msg := fmt.Sprintf(
+		"<%d>1 %s - %s - - - %s\n",
+		priority, ts.UTC().Format(time.RFC3339), h.opts.Tag, record.Message,
+	)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.conn.Write([]byte(msg))
+	return err
+}
+
+func syslogSeverity(level Level) int {
+	switch level {
+	case FatalLevel:
+		return 2 // critical
+	case ErrorLevel:
+		return 3 // error
+	case WarnLevel:
+		return 4 // warning
+	case InfoLevel:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
@@ -0,0 +1,332 @@
+// Package metrics is the Prometheus/OpenMetrics subsystem behind
+// app.EnableMetrics: a bounded-cardinality Counter/Histogram/Gauge
+// registry, an HTTP exporter serving OpenMetrics text, and a PushGateway
+// client for short-lived test runs.
+package metrics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Counter is a monotonically increasing metric.
+type Counter interface {
+	Inc()
+	Add(delta float64)
+}
+
+// Gauge is a metric that can move up or down.
+type Gauge interface {
+	Set(v float64)
+	Inc()
+	Dec()
+}
+
+// Histogram observes a distribution of values into exponential buckets.
+type Histogram interface {
+	Observe(v float64)
+}
+
+type counter struct {
+	mu    sync.Mutex
+	value float64
+}
+
+func (c *counter) Inc()              { c.Add(1) }
+func (c *counter) Add(delta float64) { c.mu.Lock(); c.value += delta; c.mu.Unlock() }
+func (c *counter) snapshot() float64 { c.mu.Lock(); defer c.mu.Unlock(); return c.value }
+
+type gauge struct {
+	mu    sync.Mutex
+	value float64
+}
+
+func (g *gauge) Set(v float64)     { g.mu.Lock(); g.value = v; g.mu.Unlock() }
+func (g *gauge) Inc()              { g.mu.Lock(); g.value++; g.mu.Unlock() }
+func (g *gauge) Dec()              { g.mu.Lock(); g.value--; g.mu.Unlock() }
+func (g *gauge) snapshot() float64 { g.mu.Lock(); defer g.mu.Unlock(); return g.value }
+
+// DefaultBuckets are the exponential buckets Histogram uses when it is
+// given no buckets. The ten package's ten_ext_cmd_duration_seconds is a
+// counter of total seconds, not a histogram, and does not use them.
+var DefaultBuckets = []float64{0.001, 0.002, 0.004, 0.008, 0.016, 0.032, 0.064, 0.128, 0.256, 0.512, 1.024, 2.048}
+
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	total   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets)+1)}
+}
+
+func (h *histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sum += v
+	h.total++
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.counts[len(h.buckets)]++
+}
+
+func (h *histogram) snapshot() (buckets []float64, counts []uint64, sum float64, total uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]float64(nil), h.buckets...), append([]uint64(nil), h.counts...), h.sum, h.total
+}
+
+type series struct {
+	name   string
+	labels map[string]string
+}
+
+func seriesKey(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		fmt.Fprintf(&b, ",%s=%s", k, labels[k])
+	}
+	return b.String()
+}
+
+// Registry holds every registered counter/gauge/histogram, bounding
+// cardinality by maxSeries: once that many series exist in total (across
+// all metric names), the label values of every further new series are
+// hashed into one of overflowBuckets buckets rather than growing without
+// limit, so the total can exceed maxSeries only by those overflow series.
+type Registry struct {
+	maxSeries int
+
+	mu         sync.Mutex
+	counters   map[string]*counter
+	gauges     map[string]*gauge
+	histograms map[string]*histogram
+	meta       map[string]series
+	preRender  func()
+}
+
+// NewRegistry builds an empty Registry bounded to maxSeries distinct
+// series.
+func NewRegistry(maxSeries int) *Registry {
+	if maxSeries <= 0 {
+		maxSeries = 10000
+	}
+	return &Registry{
+		maxSeries:  maxSeries,
+		counters:   map[string]*counter{},
+		gauges:     map[string]*gauge{},
+		histograms: map[string]*histogram{},
+		meta:       map[string]series{},
+	}
+}
+
+func (r *Registry) boundedKey(name string, labels map[string]string) (string, map[string]string) {
+	key := seriesKey(name, labels)
+	if _, exists := r.meta[key]; exists || len(r.meta) < r.maxSeries {
+		return key, labels
+	}
+
+	bounded := make(map[string]string, len(labels))
+	for k, v := range labels {
+		bounded[k] = hashLabelValue(v)
+	}
+	return seriesKey(name, bounded), bounded
+}
+
+// overflowBuckets bounds how many distinct hashed label values a
+// cardinality-bounded series can still produce once maxSeries is
+// reached: every further distinct label value collapses onto one of a
+// small, fixed number of buckets instead of creating a new series per
+// distinct value.
+const overflowBuckets = 8
+
+func hashLabelValue(v string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(v))
+	return fmt.Sprintf("overflow%d", h.Sum32()%overflowBuckets)
+}
+
+// Counter returns (creating if needed) the counter for name+labels.
+func (r *Registry) Counter(name string, labels map[string]string) Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, bounded := r.boundedKey(name, labels)
+	if c, ok := r.counters[key]; ok {
+		return c
+	}
+	c := &counter{}
+	r.counters[key] = c
+	r.meta[key] = series{name: name, labels: bounded}
+	return c
+}
+
+// Gauge returns (creating if needed) the gauge for name+labels.
+func (r *Registry) Gauge(name string, labels map[string]string) Gauge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, bounded := r.boundedKey(name, labels)
+	if g, ok := r.gauges[key]; ok {
+		return g
+	}
+	g := &gauge{}
+	r.gauges[key] = g
+	r.meta[key] = series{name: name, labels: bounded}
+	return g
+}
+
+// Histogram returns (creating if needed) the histogram for name+labels.
+func (r *Registry) Histogram(name string, labels map[string]string, buckets []float64) Histogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, bounded := r.boundedKey(name, labels)
+	if h, ok := r.histograms[key]; ok {
+		return h
+	}
+	h := newHistogram(buckets)
+	r.histograms[key] = h
+	r.meta[key] = series{name: name, labels: bounded}
+	return h
+}
+
+// SetPreRenderHook installs fn to run immediately before every
+// WriteOpenMetrics call, with r's own lock released, so fn can safely
+// pull series into r (e.g. via ImportExternal) without deadlocking.
+func (r *Registry) SetPreRenderHook(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.preRender = fn
+}
+
+// ImportExternal merges externally-tracked counters into r under the
+// same names, so a separate package's own metrics (such as
+// ten_framework/ten_runtime's process-wide counters) show up on the same
+// scrape as everything r tracks directly. snapshot keys follow "name" or
+// "name,k1=v1,k2=v2" (the format ten.MetricsSnapshot produces); values
+// are treated as the series' absolute current value, not a delta, since
+// the source is itself a monotonic counter.
+func (r *Registry) ImportExternal(snapshot map[string]float64) {
+	for key, val := range snapshot {
+		name, labels := parseExternalKey(key)
+		r.mu.Lock()
+		seriesKey, bounded := r.boundedKey(name, labels)
+		c, ok := r.counters[seriesKey]
+		if !ok {
+			c = &counter{}
+			r.counters[seriesKey] = c
+			r.meta[seriesKey] = series{name: name, labels: bounded}
+		}
+		c.mu.Lock()
+		c.value = val
+		c.mu.Unlock()
+		r.mu.Unlock()
+	}
+}
+
+func parseExternalKey(key string) (string, map[string]string) {
+	parts := strings.Split(key, ",")
+	name := parts[0]
+	if len(parts) == 1 {
+		return name, nil
+	}
+	labels := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) == 2 {
+			labels[kv[0]] = kv[1]
+		}
+	}
+	return name, labels
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// WriteOpenMetrics renders every registered series in OpenMetrics text
+// format.
+func (r *Registry) WriteOpenMetrics(w io.Writer) error {
+	r.mu.Lock()
+	preRender := r.preRender
+	r.mu.Unlock()
+	if preRender != nil {
+		preRender()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]string, 0, len(r.meta))
+	for k := range r.meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := r.meta[key]
+		labelStr := formatLabels(s.labels)
+		switch {
+		case r.counters[key] != nil:
+			if _, err := fmt.Fprintf(w, "%s%s %v\n", s.name, labelStr, r.counters[key].snapshot()); err != nil {
+				return err
+			}
+		case r.gauges[key] != nil:
+			if _, err := fmt.Fprintf(w, "%s%s %v\n", s.name, labelStr, r.gauges[key].snapshot()); err != nil {
+				return err
+			}
+		case r.histograms[key] != nil:
+			buckets, counts, sum, total := r.histograms[key].snapshot()
+			for i, b := range buckets {
+				if _, err := fmt.Fprintf(w, "%s_bucket{le=\"%v\"} %d\n", s.name, b, counts[i]); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", s.name, counts[len(counts)-1]); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "%s_sum %v\n", s.name, sum); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "%s_count %d\n", s.name, total); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := fmt.Fprintln(w, "# EOF"); err != nil {
+		return err
+	}
+	return nil
+}
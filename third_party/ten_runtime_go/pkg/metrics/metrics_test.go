@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCounterAddAndSnapshot(t *testing.T) {
+	reg := NewRegistry(0)
+	c := reg.Counter("ten_ext_cmd_total", map[string]string{"extension": "extension_a", "cmd": "hello"})
+	c.Inc()
+	c.Add(2)
+
+	var buf bytes.Buffer
+	if err := reg.WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+	if !strings.Contains(buf.String(), "ten_ext_cmd_total{cmd=\"hello\",extension=\"extension_a\"} 3") {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestHistogramObserve(t *testing.T) {
+	reg := NewRegistry(0)
+	h := reg.Histogram("ten_ext_cmd_duration_seconds", nil, nil)
+	h.Observe(0.001)
+	h.Observe(1.0)
+
+	var buf bytes.Buffer
+	if err := reg.WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+	if !strings.Contains(buf.String(), "ten_ext_cmd_duration_seconds_count 2") {
+		t.Fatalf("missing count line: %s", buf.String())
+	}
+}
+
+func TestImportExternalMergesIntoSameScrape(t *testing.T) {
+	reg := NewRegistry(0)
+	reg.Counter("local_total", nil).Inc()
+
+	reg.ImportExternal(map[string]float64{
+		"ten_ext_property_ops_total,op=set,type=int": 4,
+	})
+
+	var buf bytes.Buffer
+	if err := reg.WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "local_total 1") {
+		t.Fatalf("missing local series: %s", out)
+	}
+	if !strings.Contains(out, "ten_ext_property_ops_total{op=\"set\",type=\"int\"} 4") {
+		t.Fatalf("missing imported series: %s", out)
+	}
+}
+
+func TestPreRenderHookRunsBeforeEachScrape(t *testing.T) {
+	reg := NewRegistry(0)
+	calls := 0
+	reg.SetPreRenderHook(func() { calls++ })
+
+	var buf bytes.Buffer
+	_ = reg.WriteOpenMetrics(&buf)
+	_ = reg.WriteOpenMetrics(&buf)
+
+	if calls != 2 {
+		t.Fatalf("expected preRender to run once per scrape, got %d calls", calls)
+	}
+}
+
+func TestRegistryBoundsCardinality(t *testing.T) {
+	reg := NewRegistry(2)
+	for i := 0; i < 100; i++ {
+		reg.Counter("req_total", map[string]string{"id": fmt.Sprintf("user-%d", i)})
+	}
+
+	// The first 2 distinct label values get real series; the remaining
+	// 98 collapse onto a small, fixed number of overflow buckets instead
+	// of growing the registry by one series per distinct value.
+	if max := 2 + overflowBuckets; len(reg.meta) > max {
+		t.Fatalf("expected series count bounded to at most %d, got %d", max, len(reg.meta))
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+)
+
+// PushGatewayClient pushes a Registry's current snapshot to a Prometheus
+// PushGateway, for short-lived processes (e.g. integration tests) that
+// exit before an HTTP scraper would ever reach them.
+type PushGatewayClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewPushGatewayClient builds a client targeting the given gateway base
+// URL, job, and instance (the standard PushGateway job/instance path
+// segments).
+func NewPushGatewayClient(baseURL, job, instance string) *PushGatewayClient {
+	url := fmt.Sprintf("%s/metrics/job/%s/instance/%s", baseURL, job, instance)
+	return &PushGatewayClient{url: url, client: &http.Client{}}
+}
+
+// Push sends reg's current snapshot as a single PUT, replacing any
+// previously pushed group under the same job/instance.
+func (c *PushGatewayClient) Push(reg *Registry) error {
+	var buf bytes.Buffer
+	if err := reg.WriteOpenMetrics(&buf); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, c.url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("metrics: pushgateway returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+)
+
+// Serve starts an HTTP server exposing reg's current snapshot as
+// OpenMetrics text at path, bound to addr. It runs until ctx is
+// cancelled, at which point it shuts down gracefully.
+func Serve(ctx context.Context, addr, path string, reg *Registry) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+		_ = reg.WriteOpenMetrics(w)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return srv.Shutdown(context.Background())
+	}
+}